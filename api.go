@@ -16,21 +16,25 @@ func FilterLimit(limit int) filterOption {
 }
 
 // Record represents a Table row as a Map.
+// A NULL value is stored as nil; a column that was not fetched has no key.
+//
+// Values of timestamp columns are time.Time in UTC; a timestamp without time zone is taken as UTC.
+// Values of date columns are time.Time at midnight UTC.
+// Values of time and interval columns are *pb.TimeOfDay and *pb.Interval, as in a RowSet; both can be written as parameter values.
+// Values of bytea columns are []byte.
+// Values of array columns are []any, nested for multidimensional arrays, with nil for NULL elements.
+// Values of inet, cidr and macaddr columns are strings; values of money columns are json.Number.
+// Values of range columns are maps with keys lower, upper and bounds, e.g. "[)", or with key empty.
+// Values of multirange columns are []any of such maps.
+// Values of geometric columns are coordinates; a point is []any{x, y}, a box or polygon a list of points.
+// Values of composite columns are Records of their attributes.
 type Record map[string]any
 
 // FilterRecords queries a table using a WHERE clause. Unless option is given, the limit is 1000.
 func FilterRecords(ctx context.Context, conn Querier, metadataCacheKey, tableName string, where string, options ...filterOption) ([]Record, error) {
-	set, ok := metaCache.Get(metadataCacheKey)
-	if !ok {
-		mset, err := getMetadata(ctx, conn, tableName)
-		if err != nil {
-			return nil, err
-		}
-		metaCache.Set(metadataCacheKey, mset, defaultExpiration)
-		set = mset
-	}
-	collector := &objectCollector{
-		set: set.(*pb.RowSet),
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return nil, err
 	}
 	filter := fetchFilter{
 		where: where,
@@ -42,7 +46,14 @@ func FilterRecords(ctx context.Context, conn Querier, metadataCacheKey, tableNam
 	for _, each := range options {
 		filter = each(filter)
 	}
-	err := fetchValues(ctx, conn, collector.set, filter, collector)
+	set, err = filter.project(set)
+	if err != nil {
+		return nil, err
+	}
+	collector := &objectCollector{
+		set: set,
+	}
+	err = fetchValues(ctx, conn, collector.set, filter, collector)
 	return collector.list, err
 }
 
@@ -65,49 +76,47 @@ func FetchColumns(ctx context.Context, conn Querier, tableName string) ([]*pb.Co
 }
 
 // FetchRecords returns a list of Objects (generic maps) for the given list primary key values.
-func FetchRecords(ctx context.Context, conn Querier, metadataCacheKey, tableName string, pkv PrimaryKeysAndValues) ([]Record, error) {
-	set, ok := metaCache.Get(metadataCacheKey)
-	if !ok {
-		mset, err := getMetadata(ctx, conn, tableName)
-		if err != nil {
-			return nil, err
-		}
-		metaCache.Set(metadataCacheKey, mset, defaultExpiration)
-		set = mset
+// Options can select the columns and limit the size of bytea values.
+func FetchRecords(ctx context.Context, conn Querier, metadataCacheKey, tableName string, pkv PrimaryKeysAndValues, options ...filterOption) ([]Record, error) {
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return nil, err
 	}
-	collector := &objectCollector{
-		set: set.(*pb.RowSet),
+	filter := newFetchFilter("", 0, options)
+	filter.pkv = pkv
+	set, err = filter.project(set)
+	if err != nil {
+		return nil, err
 	}
-	filter := fetchFilter{
-		pkv: pkv,
+	collector := &objectCollector{
+		set: set,
 	}
-	err := fetchValues(ctx, conn, set.(*pb.RowSet), filter, collector)
+	err = fetchValues(ctx, conn, set, filter, collector)
 	return collector.list, err
 }
 
 // FetchObjects returns a protobuf RowSet for the given list primary key values.
-func FetchRowSet(ctx context.Context, conn Querier, metadataCacheKey, tableName string, pkv PrimaryKeysAndValues) (*pb.RowSet, error) {
-	set, ok := metaCache.Get(metadataCacheKey)
-	if !ok {
-		mset, err := getMetadata(ctx, conn, tableName)
-		if err != nil {
-			return nil, err
-		}
-		metaCache.Set(metadataCacheKey, mset, defaultExpiration)
-		set = mset
-	}
-	tset := set.(*pb.RowSet)
+// Options can select the columns and limit the size of bytea values.
+func FetchRowSet(ctx context.Context, conn Querier, metadataCacheKey, tableName string, pkv PrimaryKeysAndValues, options ...filterOption) (*pb.RowSet, error) {
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return nil, err
+	}
+	filter := newFetchFilter("", 0, options)
+	filter.pkv = pkv
+	set, err = filter.project(set)
+	if err != nil {
+		return nil, err
+	}
 	collector := &rowsetCollector{
 		// create a new with metadata from the cached set
 		set: &pb.RowSet{
-			TableName:     tset.TableName,
-			ColumnSchemas: tset.ColumnSchemas,
+			SchemaName:    set.SchemaName,
+			TableName:     set.TableName,
+			ColumnSchemas: set.ColumnSchemas,
 		},
 	}
-	filter := fetchFilter{
-		pkv: pkv,
-	}
-	err := fetchValues(ctx, conn, tset, filter, collector)
+	err = fetchValues(ctx, conn, set, filter, collector)
 	return collector.set, err
 }
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/emicklei/anyrow/pb"
 	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 func TestFilterObjects(t *testing.T) {
@@ -51,6 +52,21 @@ func TestFilterObjectsLimit(t *testing.T) {
 	}
 }
 
+func TestFilterObjectsOrderAndColumns(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{float64(42)}}
+	list, err := FilterRecords(ctx, conn, "testkey", "test", "", FilterColumns("num"), FilterOrderBy("str", true), FilterOrderBy("num", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `SELECT "num" FROM public.test WHERE true ORDER BY "str" DESC,"num" LIMIT 1000`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(list[0]), 1; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
 func TestFetchObjects(t *testing.T) {
 	ctx := context.Background()
 	conn := new(mockQuerier)
@@ -92,7 +108,7 @@ func TestFetchRowSet(t *testing.T) {
 		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
 	}
 	mp := set.RowMap(0)
-	if got, want := mp["num"], float32(42); got != want {
+	if got, want := mp["num"], float64(42); got != want {
 		t.Errorf("got [%v]:%T want [%v]:%T", got, got, want, want)
 	}
 	if got, want := mp["str"], "shoesize"; got != want {
@@ -103,25 +119,75 @@ func TestFetchRowSet(t *testing.T) {
 type mockQuerier struct {
 	sql  string
 	args []any
+	// all queries in order
+	sqls []string
+	// values to return for a row, defaults to a string and a number
+	values []any
+	// rows to return for consecutive queries, before using values
+	results [][][]any
+	// number of rows to return, defaults to 1
+	rowCount int
+	// fields of the rows to return
+	fields []pgconn.FieldDescription
+	// rows returned by the last query
+	rows *mockRows
 }
 type mockRows struct {
 	pgx.Rows
+	fields []pgconn.FieldDescription
 	values []any
-	next   bool
+	// values of each row, if set
+	rows   [][]any
+	count  int
+	closed bool
 }
 
 func (m *mockRows) Next() bool {
-	n := m.next
-	m.next = false
-	return n
+	if m.count == 0 {
+		return false
+	}
+	if m.rows != nil {
+		m.values = m.rows[len(m.rows)-m.count]
+	}
+	m.count--
+	return true
 }
-func (m *mockRows) Close()                 {}
+func (m *mockRows) Close()                 { m.closed = true }
+func (m *mockRows) Err() error             { return nil }
 func (m *mockRows) Values() ([]any, error) { return m.values, nil }
+func (m *mockRows) RawValues() [][]byte    { return nil }
+func (m *mockRows) Scan(dest ...any) error {
+	for i, each := range dest {
+		reflect.ValueOf(each).Elem().Set(reflect.ValueOf(m.values[i]))
+	}
+	return nil
+}
+func (m *mockRows) FieldDescriptions() []pgconn.FieldDescription {
+	return m.fields
+}
+func (m *mockRows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag("MOCK 1")
+}
 
 func (m *mockQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
 	m.sql = sql
 	m.args = args
+	m.sqls = append(m.sqls, sql)
+	count := m.rowCount
+	if count == 0 {
+		count = 1
+	}
+	if len(m.results) > 0 {
+		m.rows = &mockRows{rows: m.results[0], count: len(m.results[0]), fields: m.fields}
+		m.results = m.results[1:]
+		return m.rows, nil
+	}
+	if m.values != nil {
+		m.rows = &mockRows{count: count, values: m.values, fields: m.fields}
+		return m.rows, nil
+	}
 	s := "shoesize"
 	i := float64(42)
-	return &mockRows{next: true, values: []any{s, i}}, nil
+	m.rows = &mockRows{count: count, values: []any{s, i}}
+	return m.rows, nil
 }
@@ -0,0 +1,107 @@
+package anyrow
+
+import (
+	"sync"
+	"time"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+// ValueCodec converts a column value, as returned by pgx, and writes it for a Record or RowSet.
+// Values of data types unknown to pgx, such as those of extensions, are returned as strings.
+type ValueCodec interface {
+	Store(value any, w ValueWriter) error
+}
+
+// ValueCodecFunc is a function that implements ValueCodec.
+type ValueCodecFunc func(value any, w ValueWriter) error
+
+// Store calls f(value, w).
+func (f ValueCodecFunc) Store(value any, w ValueWriter) error { return f(value, w) }
+
+// ValueWriter is used by a ValueCodec to write a converted column value.
+// Each write method maps to a variant of pb.ColumnValue.
+type ValueWriter interface {
+	// WriteNull writes a null_value.
+	WriteNull()
+	// WriteString writes a string_value.
+	WriteString(value string)
+	// WriteBytes writes a bytes_value.
+	WriteBytes(value []byte)
+	// WriteInt64 writes a number_integer_value.
+	WriteInt64(value int64)
+	// WriteFloat32 writes a number_float_value.
+	WriteFloat32(value float32)
+	// WriteFloat64 writes a number_double_value.
+	WriteFloat64(value float64)
+	// WriteDecimal writes the canonical text of a numeric as decimal_value.
+	WriteDecimal(value string)
+	// WriteBool writes a bool_value.
+	WriteBool(value bool)
+	// WriteObject writes the JSON encoding of a value as object_value.
+	WriteObject(value any)
+	// WriteTimestamp writes a timestamp_value.
+	WriteTimestamp(value time.Time)
+	// WriteDate writes the date part of a time as date_value.
+	WriteDate(value time.Time)
+	// WriteArray writes the elements, as they would be stored in a Record, as array_value.
+	WriteArray(elements []any)
+}
+
+var (
+	codecMutex   sync.RWMutex
+	codecsByName = map[string]ValueCodec{}
+	codecsByOID  = map[uint32]ValueCodec{}
+)
+
+// RegisterCodec registers a codec for a data type by its name, e.g. "geometry" or "ltree".
+// The name is matched with the udt_name and the data_type of a column.
+// A registered codec takes precedence over the built-in conversions.
+func RegisterCodec(typeName string, codec ValueCodec) {
+	codecMutex.Lock()
+	defer codecMutex.Unlock()
+	codecsByName[typeName] = codec
+}
+
+// RegisterCodecOID registers a codec for a data type by its OID.
+// A registered codec takes precedence over the built-in conversions.
+func RegisterCodecOID(oid uint32, codec ValueCodec) {
+	codecMutex.Lock()
+	defer codecMutex.Unlock()
+	codecsByOID[oid] = codec
+}
+
+// codecFor returns the registered codec for the data type of a column, if any.
+func codecFor(schema *pb.ColumnSchema) (ValueCodec, bool) {
+	codecMutex.RLock()
+	defer codecMutex.RUnlock()
+	if codec, ok := codecsByOID[schema.TypeOid]; ok && schema.TypeOid != 0 {
+		return codec, true
+	}
+	if codec, ok := codecsByName[schema.UdtName]; ok && schema.UdtName != "" {
+		return codec, true
+	}
+	codec, ok := codecsByName[schema.TypeName]
+	return codec, ok
+}
+
+// collectorWriter is a ValueWriter for one column of the current row of a collector.
+type collectorWriter struct {
+	collector valueCollector
+	index     int
+}
+
+func (w collectorWriter) WriteNull()                 { w.collector.storeNull(w.index) }
+func (w collectorWriter) WriteString(value string)   { w.collector.storeString(w.index, value) }
+func (w collectorWriter) WriteBytes(value []byte)    { w.collector.storeBytes(w.index, value) }
+func (w collectorWriter) WriteInt64(value int64)     { w.collector.storeInt64(w.index, value) }
+func (w collectorWriter) WriteFloat32(value float32) { w.collector.storeFloat32(w.index, value) }
+func (w collectorWriter) WriteFloat64(value float64) { w.collector.storeFloat64(w.index, value) }
+func (w collectorWriter) WriteDecimal(value string)  { w.collector.storeDecimal(w.index, value) }
+func (w collectorWriter) WriteBool(value bool)       { w.collector.storeBool(w.index, value) }
+func (w collectorWriter) WriteObject(value any)      { w.collector.storeDefault(w.index, value) }
+func (w collectorWriter) WriteTimestamp(value time.Time) {
+	w.collector.storeTimestamp(w.index, value.UTC())
+}
+func (w collectorWriter) WriteDate(value time.Time) { w.collector.storeDate(w.index, value) }
+func (w collectorWriter) WriteArray(elements []any) { w.collector.storeArray(w.index, elements) }
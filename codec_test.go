@@ -0,0 +1,48 @@
+package anyrow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+func TestRegisterCodec(t *testing.T) {
+	RegisterCodec("test_ltree", ValueCodecFunc(func(value any, w ValueWriter) error {
+		w.WriteObject(strings.Split(value.(string), "."))
+		return nil
+	}))
+	RegisterCodecOID(99001, ValueCodecFunc(func(value any, w ValueWriter) error {
+		w.WriteString(strings.ToUpper(value.(string)))
+		return nil
+	}))
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "path", TypeName: "USER-DEFINED", UdtName: "test_ltree", TypeOid: 99000},
+		{Name: "code", TypeName: "USER-DEFINED", UdtName: "test_citext", TypeOid: 99001},
+	}}
+	collector := &rowsetCollector{set: set}
+	collector.nextRow(2)
+	if err := storeValues(set, []any{"top.science.astronomy", "abc"}, collector); err != nil {
+		t.Fatal(err)
+	}
+	mp := set.RowMap(0)
+	if got, want := mp["path"], `["top","science","astronomy"]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := mp["code"], "ABC"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestRegisterCodecError(t *testing.T) {
+	RegisterCodec("test_failing", ValueCodecFunc(func(value any, w ValueWriter) error {
+		return errors.New("cannot convert")
+	}))
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{{Name: "bad", UdtName: "test_failing"}}}
+	collector := &objectCollector{set: set}
+	collector.nextRow(1)
+	if err := storeValues(set, []any{"x"}, collector); err == nil {
+		t.Fatal("error expected")
+	}
+}
@@ -2,17 +2,35 @@ package anyrow
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type valueCollector interface {
 	nextRow(length int)
+	storeNull(index int)
 	storeDefault(index int, value any)
 	storeBool(index int, value bool)
 	storeString(index int, value string)
+	storeBytes(index int, value []byte)
 	storeFloat32(index int, value float32)
+	storeFloat64(index int, value float64)
+	storeDecimal(index int, value string)
 	storeInt64(index int, value int64)
+	storeTimestamp(index int, value time.Time)
+	storeDate(index int, value time.Time)
+	storeTime(index int, value pgtype.Time)
+	storeInterval(index int, value pgtype.Interval)
+	// storeArray stores the elements as they would be stored in a Record, nested for multidimensional arrays.
+	storeArray(index int, elements []any)
+	// storeRange stores the bounds as they would be stored in a Record.
+	storeRange(index int, lower, upper any, bounds string, empty bool)
+	// storeComposite stores the attribute values of a composite value.
+	storeComposite(index int, schemas []*pb.ColumnSchema, record Record)
 }
 
 type objectCollector struct {
@@ -21,6 +39,9 @@ type objectCollector struct {
 	set    *pb.RowSet
 }
 
+func (o *objectCollector) storeNull(index int) {
+	o.object[o.set.ColumnSchemas[index].Name] = nil
+}
 func (o *objectCollector) storeDefault(index int, value any) {
 	o.object[o.set.ColumnSchemas[index].Name] = value
 }
@@ -30,13 +51,49 @@ func (o *objectCollector) storeBool(index int, value bool) {
 func (o *objectCollector) storeString(index int, value string) {
 	o.object[o.set.ColumnSchemas[index].Name] = value
 }
+func (o *objectCollector) storeBytes(index int, value []byte) {
+	o.object[o.set.ColumnSchemas[index].Name] = value
+}
 func (o *objectCollector) storeFloat32(index int, value float32) {
 	o.object[o.set.ColumnSchemas[index].Name] = value
 }
+func (o *objectCollector) storeFloat64(index int, value float64) {
+	o.object[o.set.ColumnSchemas[index].Name] = value
+}
+func (o *objectCollector) storeDecimal(index int, value string) {
+	o.object[o.set.ColumnSchemas[index].Name] = json.Number(value)
+}
 func (o *objectCollector) storeInt64(index int, value int64) {
 	o.object[o.set.ColumnSchemas[index].Name] = value
 }
 
+func (o *objectCollector) storeTimestamp(index int, value time.Time) {
+	o.object[o.set.ColumnSchemas[index].Name] = value
+}
+func (o *objectCollector) storeDate(index int, value time.Time) {
+	o.object[o.set.ColumnSchemas[index].Name] = value
+}
+func (o *objectCollector) storeTime(index int, value pgtype.Time) {
+	o.object[o.set.ColumnSchemas[index].Name] = timeOfDayOf(value)
+}
+func (o *objectCollector) storeInterval(index int, value pgtype.Interval) {
+	o.object[o.set.ColumnSchemas[index].Name] = intervalOf(value)
+}
+
+func (o *objectCollector) storeArray(index int, elements []any) {
+	o.object[o.set.ColumnSchemas[index].Name] = elements
+}
+func (o *objectCollector) storeRange(index int, lower, upper any, bounds string, empty bool) {
+	if empty {
+		o.object[o.set.ColumnSchemas[index].Name] = map[string]any{"empty": true}
+		return
+	}
+	o.object[o.set.ColumnSchemas[index].Name] = map[string]any{"lower": lower, "upper": upper, "bounds": bounds}
+}
+func (o *objectCollector) storeComposite(index int, schemas []*pb.ColumnSchema, record Record) {
+	o.object[o.set.ColumnSchemas[index].Name] = record
+}
+
 func (o *objectCollector) nextRow(length int) {
 	o.object = make(map[string]any, length)
 	o.list = append(o.list, o.object)
@@ -47,6 +104,9 @@ type rowsetCollector struct {
 	row *pb.Row
 }
 
+func (r *rowsetCollector) storeNull(index int) {
+	r.row.Columns[index] = columnValueOf(nil)
+}
 func (r *rowsetCollector) storeDefault(index int, value any) {
 	data, _ := json.Marshal(value)
 	cell := new(pb.ColumnValue)
@@ -63,19 +123,223 @@ func (r *rowsetCollector) storeString(index int, value string) {
 	cell.JsonValue = &pb.ColumnValue_StringValue{StringValue: value}
 	r.row.Columns[index] = cell
 }
+func (r *rowsetCollector) storeBytes(index int, value []byte) {
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_BytesValue{BytesValue: value}
+	r.row.Columns[index] = cell
+}
 func (r *rowsetCollector) storeFloat32(index int, value float32) {
 	cell := new(pb.ColumnValue)
 	cell.JsonValue = &pb.ColumnValue_NumberFloatValue{NumberFloatValue: value}
 	r.row.Columns[index] = cell
 }
+func (r *rowsetCollector) storeFloat64(index int, value float64) {
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_NumberDoubleValue{NumberDoubleValue: value}
+	r.row.Columns[index] = cell
+}
+func (r *rowsetCollector) storeDecimal(index int, value string) {
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_DecimalValue{DecimalValue: value}
+	r.row.Columns[index] = cell
+}
 func (r *rowsetCollector) storeInt64(index int, value int64) {
 	cell := new(pb.ColumnValue)
 	cell.JsonValue = &pb.ColumnValue_NumberIntegerValue{NumberIntegerValue: value}
 	r.row.Columns[index] = cell
 }
 
+func (r *rowsetCollector) storeTimestamp(index int, value time.Time) {
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_TimestampValue{TimestampValue: timestamppb.New(value)}
+	r.row.Columns[index] = cell
+}
+func (r *rowsetCollector) storeDate(index int, value time.Time) {
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_DateValue{DateValue: dateOf(value)}
+	r.row.Columns[index] = cell
+}
+func (r *rowsetCollector) storeTime(index int, value pgtype.Time) {
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_TimeValue{TimeValue: timeOfDayOf(value)}
+	r.row.Columns[index] = cell
+}
+func (r *rowsetCollector) storeInterval(index int, value pgtype.Interval) {
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_IntervalValue{IntervalValue: intervalOf(value)}
+	r.row.Columns[index] = cell
+}
+
+func (r *rowsetCollector) storeArray(index int, elements []any) {
+	schema := r.set.ColumnSchemas[index]
+	if len(schema.AttributeSchemas) > 0 {
+		r.row.Columns[index] = compositeArrayOf(schema.AttributeSchemas, elements)
+		return
+	}
+	r.row.Columns[index] = typedColumnValueOf(schema.TypeOid, elements)
+}
+func (r *rowsetCollector) storeRange(index int, lower, upper any, bounds string, empty bool) {
+	elementOID, _ := rangeElementOID(r.set.ColumnSchemas[index].TypeOid)
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_RangeValue{RangeValue: rangeOf(elementOID, lower, upper, bounds, empty)}
+	r.row.Columns[index] = cell
+}
+func (r *rowsetCollector) storeComposite(index int, schemas []*pb.ColumnSchema, record Record) {
+	cell := new(pb.ColumnValue)
+	cell.JsonValue = &pb.ColumnValue_CompositeValue{CompositeValue: rowWithSchemaOf(schemas, record)}
+	r.row.Columns[index] = cell
+}
+
 func (r *rowsetCollector) nextRow(length int) {
 	r.row = new(pb.Row)
 	r.row.Columns = make([]*pb.ColumnValue, length)
 	r.set.Rows = append(r.set.Rows, r.row)
 }
+
+// columnValueOf returns the column value for a Go value as stored in a Record.
+func columnValueOf(value any) *pb.ColumnValue {
+	cell := new(pb.ColumnValue)
+	switch v := value.(type) {
+	case nil:
+		cell.JsonValue = &pb.ColumnValue_NullValue{NullValue: structpb.NullValue_NULL_VALUE}
+	case string:
+		cell.JsonValue = &pb.ColumnValue_StringValue{StringValue: v}
+	case []byte:
+		cell.JsonValue = &pb.ColumnValue_BytesValue{BytesValue: v}
+	case int64:
+		cell.JsonValue = &pb.ColumnValue_NumberIntegerValue{NumberIntegerValue: v}
+	case int32:
+		cell.JsonValue = &pb.ColumnValue_NumberIntegerValue{NumberIntegerValue: int64(v)}
+	case int:
+		cell.JsonValue = &pb.ColumnValue_NumberIntegerValue{NumberIntegerValue: int64(v)}
+	case float32:
+		cell.JsonValue = &pb.ColumnValue_NumberFloatValue{NumberFloatValue: v}
+	case float64:
+		cell.JsonValue = &pb.ColumnValue_NumberDoubleValue{NumberDoubleValue: v}
+	case json.Number:
+		cell.JsonValue = &pb.ColumnValue_DecimalValue{DecimalValue: string(v)}
+	case time.Time:
+		cell.JsonValue = &pb.ColumnValue_TimestampValue{TimestampValue: timestamppb.New(v)}
+	case *pb.TimeOfDay:
+		cell.JsonValue = &pb.ColumnValue_TimeValue{TimeValue: v}
+	case *pb.Interval:
+		cell.JsonValue = &pb.ColumnValue_IntervalValue{IntervalValue: v}
+	case pgtype.Time:
+		cell.JsonValue = &pb.ColumnValue_TimeValue{TimeValue: timeOfDayOf(v)}
+	case pgtype.Interval:
+		cell.JsonValue = &pb.ColumnValue_IntervalValue{IntervalValue: intervalOf(v)}
+	case bool:
+		cell.JsonValue = &pb.ColumnValue_BoolValue{BoolValue: v}
+	case []any:
+		array := &pb.Array{Elements: make([]*pb.ColumnValue, len(v))}
+		for i, each := range v {
+			array.Elements[i] = columnValueOf(each)
+		}
+		cell.JsonValue = &pb.ColumnValue_ArrayValue{ArrayValue: array}
+	default:
+		data, _ := json.Marshal(value)
+		cell.JsonValue = &pb.ColumnValue_ObjectValue{ObjectValue: string(data)}
+	}
+	return cell
+}
+
+// typedColumnValueOf returns the column value for a Go value as stored in a Record for a data type.
+// Unlike columnValueOf, it returns dates, also those of arrays and ranges, as date values instead of timestamps,
+// and ranges of arrays and multiranges as range values.
+func typedColumnValueOf(typeOID uint32, value any) *pb.ColumnValue {
+	switch v := value.(type) {
+	case time.Time:
+		if typeOID == pgtype.DateOID {
+			return &pb.ColumnValue{JsonValue: &pb.ColumnValue_DateValue{DateValue: dateOf(v)}}
+		}
+	case map[string]any:
+		// a range as stored in a Record
+		if elementOID, ok := rangeElementOID(typeOID); ok {
+			bounds, _ := v["bounds"].(string)
+			empty, _ := v["empty"].(bool)
+			return &pb.ColumnValue{JsonValue: &pb.ColumnValue_RangeValue{RangeValue: rangeOf(elementOID, v["lower"], v["upper"], bounds, empty)}}
+		}
+	case []any:
+		elementOID, ok := arrayElementOID(typeOID)
+		if !ok {
+			// the ranges of a multirange
+			elementOID, ok = multirangeRangeOID(typeOID)
+		}
+		if !ok {
+			break
+		}
+		array := &pb.Array{Elements: make([]*pb.ColumnValue, len(v))}
+		for i, each := range v {
+			if _, nested := each.([]any); nested {
+				// a row of a multidimensional array
+				array.Elements[i] = typedColumnValueOf(typeOID, each)
+				continue
+			}
+			array.Elements[i] = typedColumnValueOf(elementOID, each)
+		}
+		return &pb.ColumnValue{JsonValue: &pb.ColumnValue_ArrayValue{ArrayValue: array}}
+	}
+	return columnValueOf(value)
+}
+
+// rangeOf returns a range with bounds as stored in a Record.
+func rangeOf(elementOID uint32, lower, upper any, bounds string, empty bool) *pb.Range {
+	value := &pb.Range{Empty: empty}
+	if !empty {
+		value.Lower = typedColumnValueOf(elementOID, lower)
+		value.Upper = typedColumnValueOf(elementOID, upper)
+		value.Bounds = bounds
+	}
+	return value
+}
+
+// rowWithSchemaOf returns the attribute values of a composite value stored as a Record, with their schemas.
+func rowWithSchemaOf(schemas []*pb.ColumnSchema, record Record) *pb.RowWithSchema {
+	row := &pb.RowWithSchema{Schemas: schemas, Columns: make([]*pb.ColumnValue, len(schemas))}
+	for i, each := range schemas {
+		value := record[each.Name]
+		switch nested := value.(type) {
+		case Record:
+			row.Columns[i] = &pb.ColumnValue{JsonValue: &pb.ColumnValue_CompositeValue{CompositeValue: rowWithSchemaOf(each.AttributeSchemas, nested)}}
+			continue
+		case []any:
+			if len(each.AttributeSchemas) > 0 {
+				row.Columns[i] = compositeArrayOf(each.AttributeSchemas, nested)
+				continue
+			}
+		}
+		row.Columns[i] = typedColumnValueOf(each.TypeOid, value)
+	}
+	return row
+}
+
+// compositeArrayOf returns the elements of an array of a composite type, stored as Records, with their schemas.
+func compositeArrayOf(schemas []*pb.ColumnSchema, elements []any) *pb.ColumnValue {
+	array := &pb.Array{Elements: make([]*pb.ColumnValue, len(elements))}
+	for i, each := range elements {
+		switch v := each.(type) {
+		case Record:
+			array.Elements[i] = &pb.ColumnValue{JsonValue: &pb.ColumnValue_CompositeValue{CompositeValue: rowWithSchemaOf(schemas, v)}}
+		case []any:
+			array.Elements[i] = compositeArrayOf(schemas, v)
+		default:
+			array.Elements[i] = columnValueOf(each)
+		}
+	}
+	return &pb.ColumnValue{JsonValue: &pb.ColumnValue_ArrayValue{ArrayValue: array}}
+}
+
+// dateOf returns the date of a time as stored in a RowSet.
+func dateOf(value time.Time) *pb.Date {
+	return &pb.Date{Year: int32(value.Year()), Month: int32(value.Month()), Day: int32(value.Day())}
+}
+
+// timeOfDayOf returns the time as stored in a Record and a RowSet.
+func timeOfDayOf(value pgtype.Time) *pb.TimeOfDay {
+	return &pb.TimeOfDay{Microseconds: value.Microseconds}
+}
+
+// intervalOf returns the interval as stored in a Record and a RowSet.
+func intervalOf(value pgtype.Interval) *pb.Interval {
+	return &pb.Interval{Microseconds: value.Microseconds, Days: value.Days, Months: value.Months}
+}
@@ -0,0 +1,88 @@
+package anyrow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+var cursorSequence atomic.Int64
+
+// StreamRowSets queries a table using a WHERE clause through a server-side cursor and yields the rows in RowSets of at most chunkSize rows.
+// All RowSets share the column schemas of the cached metadata, or as selected by FilterColumns.
+// A cursor only exists inside a transaction so conn must be a pgx.Tx (or any Querier within a transaction).
+// The cursor is closed when all rows are read, the consumer stops the iteration or the context is done.
+func StreamRowSets(ctx context.Context, conn Querier, metadataCacheKey, tableName string, where string, chunkSize int, options ...filterOption) iter.Seq2[*pb.RowSet, error] {
+	return func(yield func(*pb.RowSet, error) bool) {
+		if chunkSize <= 0 {
+			yield(nil, errors.New("chunkSize parameter must be greater than zero"))
+			return
+		}
+		set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		filter := newFetchFilter(where, 0, options)
+		set, err = filter.project(set)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		cursor := fmt.Sprintf("anyrow_cursor_%d", cursorSequence.Add(1))
+		sql, args := selectStatement(set, filter)
+		if err := execQuery(ctx, conn, fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", cursor, sql), args...); err != nil {
+			yield(nil, err)
+			return
+		}
+		defer func() {
+			if err := execQuery(context.WithoutCancel(ctx), conn, "CLOSE "+cursor); err != nil {
+				slog.Debug("[anyrow] close cursor failed", "cursor", cursor, "err", err)
+			}
+		}()
+		fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", chunkSize, cursor)
+		for {
+			collector := &rowsetCollector{
+				set: &pb.RowSet{
+					SchemaName:    set.SchemaName,
+					TableName:     set.TableName,
+					ColumnSchemas: set.ColumnSchemas,
+				},
+			}
+			dbrows, err := conn.Query(ctx, fetch)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if err := streamValues(ctx, dbrows, set, collector, func() bool { return true }); err != nil {
+				yield(nil, err)
+				return
+			}
+			if len(collector.set.Rows) == 0 {
+				return
+			}
+			if !yield(collector.set, nil) {
+				return
+			}
+			if len(collector.set.Rows) < chunkSize {
+				return
+			}
+		}
+	}
+}
+
+// execQuery runs a statement that returns no rows using a Querier.
+func execQuery(ctx context.Context, conn Querier, sql string, args ...any) error {
+	slog.Debug("execQuery", "sql", sql, "params", args)
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
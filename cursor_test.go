@@ -0,0 +1,50 @@
+package anyrow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestStreamRowSets(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	count := 0
+	for each, err := range StreamRowSets(ctx, conn, "testkey", "test", "", 10) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(each.Rows), 1; got != want {
+			t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+		}
+		if got, want := len(each.ColumnSchemas), 2; got != want {
+			t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+		}
+		count++
+	}
+	if got, want := count, 1; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	cursor := conn.sqls[len(conn.sqls)-1][len("CLOSE "):]
+	want := []string{
+		`DECLARE ` + cursor + ` NO SCROLL CURSOR FOR SELECT "str","num" FROM public.test WHERE true`,
+		`FETCH FORWARD 10 FROM ` + cursor,
+		`CLOSE ` + cursor,
+	}
+	if got := conn.sqls; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestStreamRowSetsChunkSize(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	for _, err := range StreamRowSets(ctx, conn, "testkey", "test", "", 0) {
+		if err == nil {
+			t.Fatal("error expected")
+		}
+	}
+	if len(conn.sqls) != 0 {
+		t.Errorf("unexpected queries: %v", conn.sqls)
+	}
+}
@@ -0,0 +1,48 @@
+package anyrow
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// DeleteRecords deletes the rows identified by the key values. Deleting without key values is refused,
+// as is deleting by columns that are not part of the primary key unless the WriteNonKeyColumns option is given.
+// With the WriteImages option, the deleted rows are returned as the before image.
+func DeleteRecords(ctx context.Context, conn Querier, metadataCacheKey, tableName string, pkv PrimaryKeysAndValues, options ...writeOption) (WriteResult, error) {
+	result := WriteResult{}
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return result, err
+	}
+	cfg := newWriteConfig(options)
+	if err := cfg.checkKeyColumns(set, pkv); err != nil {
+		return result, err
+	}
+	filter := fetchFilter{
+		pkv: pkv,
+	}
+	qb := new(strings.Builder)
+	qb.WriteString("DELETE FROM ")
+	qb.WriteString(set.SchemaName)
+	qb.WriteRune('.')
+	qb.WriteString(set.TableName)
+	qb.WriteString(" WHERE ")
+	filter.whereOn(qb)
+	before := &objectCollector{set: set}
+	if cfg.images {
+		returningOn(qb, set)
+	}
+	sql := qb.String()
+	slog.Debug("DeleteRecords", "sql", sql, "params", pkv.parameterValues())
+	dbrows, err := conn.Query(ctx, sql, pkv.parameterValues()...)
+	if err != nil {
+		return result, err
+	}
+	if err := collectValues(dbrows, set, before); err != nil {
+		return result, err
+	}
+	result.RowsAffected = dbrows.CommandTag().RowsAffected()
+	result.Before = before.list
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package anyrow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestDeleteRecords(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7), "shoe"}}
+	result, err := DeleteRecords(ctx, conn, "testpkkey", "pktest", NewPrimaryKeyAndValues("id", 7, 8), WriteImages())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `DELETE FROM public.pktest WHERE id IN ($1,$2) RETURNING "id","str"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := result.Before[0]["id"], int64(7); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestDeleteRecordsWithoutKey(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	_, err := DeleteRecords(ctx, conn, "testpkkey", "pktest", PrimaryKeysAndValues{})
+	if err == nil {
+		t.Fatal("error expected")
+	}
+	if conn.sql != "" {
+		t.Errorf("unexpected query: %q", conn.sql)
+	}
+}
+
+func TestDeleteRecordsNonKeyColumn(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	if _, err := DeleteRecords(ctx, conn, "testpkkey", "pktest", NewPrimaryKeyAndValues("str", "shoe")); err == nil {
+		t.Fatal("error expected")
+	}
+	if conn.sql != "" {
+		t.Errorf("unexpected query: %q", conn.sql)
+	}
+	if _, err := DeleteRecords(ctx, conn, "testpkkey", "pktest", NewPrimaryKeyAndValues("str", "shoe"), WriteNonKeyColumns()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `DELETE FROM public.pktest WHERE str IN ($1)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestDeleteRecordsPartialCompositeKey(t *testing.T) {
+	setupLineKeys()
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	// order_id alone selects all lines of an order
+	if _, err := DeleteRecords(ctx, conn, "public.lines", "lines", NewPrimaryKeyAndValues("order_id", 1)); err == nil {
+		t.Fatal("error expected")
+	}
+	if _, err := UpdateRecord(ctx, conn, "public.lines", "lines", NewPrimaryKeyAndValues("order_id", 1), Record{"product": "shoe"}); err == nil {
+		t.Fatal("error expected")
+	}
+	if conn.sql != "" {
+		t.Errorf("unexpected query: %q", conn.sql)
+	}
+	pkv := NewPrimaryKeysAndValues([]PrimaryKeyAndValue{{Column: "order_id", Value: 1}, {Column: "nr", Value: 2}})
+	if _, err := DeleteRecords(ctx, conn, "public.lines", "lines", pkv); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DeleteRecords(ctx, conn, "public.lines", "lines", NewPrimaryKeyAndValues("order_id", 1), WriteNonKeyColumns()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteRecordsImagesMoney(t *testing.T) {
+	metaCache.Set("testmoneykey", &pb.RowSet{SchemaName: "public", TableName: "prices", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "price", TypeName: "money", TypeOid: moneyOID},
+	}}, 0)
+	price := pgtype.Numeric{}
+	price.Scan("12.34")
+	conn := &mockQuerier{values: []any{int64(7), price}}
+	result, err := DeleteRecords(context.Background(), conn, "testmoneykey", "prices", NewPrimaryKeyAndValues("id", 7), WriteImages())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// money is returned as numeric, as when fetched
+	if got, want := conn.sql, `DELETE FROM public.prices WHERE id IN ($1) RETURNING "id","price"::numeric AS "price"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := result.Before[0]["price"], json.Number("12.34"); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
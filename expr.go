@@ -0,0 +1,187 @@
+package anyrow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+// Expr is a node of a filter expression that is rendered as an SQL condition with parameters.
+// Column names are validated against the table metadata and quoted; values are never part of the SQL.
+// An Expr can be encoded to and decoded from JSON, e.g. {"op":"eq","column":"name","values":["Bob"]}.
+type Expr struct {
+	Op     string `json:"op"`
+	Column string `json:"column,omitempty"`
+	Values []any  `json:"values,omitempty"`
+	Args   []Expr `json:"args,omitempty"`
+}
+
+// comparison operators by Op
+var exprComparisons = map[string]string{
+	"eq":    "=",
+	"ne":    "<>",
+	"lt":    "<",
+	"le":    "<=",
+	"gt":    ">",
+	"ge":    ">=",
+	"like":  "LIKE",
+	"ilike": "ILIKE",
+}
+
+// Eq returns the condition column = value.
+func Eq(column string, value any) Expr { return Expr{Op: "eq", Column: column, Values: []any{value}} }
+
+// Ne returns the condition column <> value.
+func Ne(column string, value any) Expr { return Expr{Op: "ne", Column: column, Values: []any{value}} }
+
+// Lt returns the condition column < value.
+func Lt(column string, value any) Expr { return Expr{Op: "lt", Column: column, Values: []any{value}} }
+
+// Le returns the condition column <= value.
+func Le(column string, value any) Expr { return Expr{Op: "le", Column: column, Values: []any{value}} }
+
+// Gt returns the condition column > value.
+func Gt(column string, value any) Expr { return Expr{Op: "gt", Column: column, Values: []any{value}} }
+
+// Ge returns the condition column >= value.
+func Ge(column string, value any) Expr { return Expr{Op: "ge", Column: column, Values: []any{value}} }
+
+// Like returns the condition column LIKE pattern.
+func Like(column string, pattern string) Expr {
+	return Expr{Op: "like", Column: column, Values: []any{pattern}}
+}
+
+// ILike returns the condition column ILIKE pattern (case-insensitive).
+func ILike(column string, pattern string) Expr {
+	return Expr{Op: "ilike", Column: column, Values: []any{pattern}}
+}
+
+// In returns the condition column IN (values...).
+func In(column string, values ...any) Expr { return Expr{Op: "in", Column: column, Values: values} }
+
+// IsNull returns the condition column IS NULL.
+func IsNull(column string) Expr { return Expr{Op: "isnull", Column: column} }
+
+// Between returns the condition column BETWEEN low AND high.
+func Between(column string, low, high any) Expr {
+	return Expr{Op: "between", Column: column, Values: []any{low, high}}
+}
+
+// And returns the conjunction of conditions; true if empty.
+func And(args ...Expr) Expr { return Expr{Op: "and", Args: args} }
+
+// Or returns the disjunction of conditions; false if empty.
+func Or(args ...Expr) Expr { return Expr{Op: "or", Args: args} }
+
+// Not returns the negation of a condition.
+func Not(arg Expr) Expr { return Expr{Op: "not", Args: []Expr{arg}} }
+
+// ParseExprJSON decodes a filter expression from JSON.
+// Numbers are decoded as json.Number to keep their precision.
+func ParseExprJSON(data []byte) (Expr, error) {
+	var e Expr
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&e); err != nil {
+		return e, fmt.Errorf("invalid filter expression: %w", err)
+	}
+	return e, nil
+}
+
+// validate returns an error if the expression is malformed or refers to a column that is not part of the table.
+func (e Expr) validate(metaSet *pb.RowSet) error {
+	switch e.Op {
+	case "and", "or", "not":
+		if e.Op == "not" && len(e.Args) != 1 {
+			return fmt.Errorf("filter operator %q requires one argument", e.Op)
+		}
+		for _, each := range e.Args {
+			if err := each.validate(metaSet); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if columnSchemaNamed(metaSet, e.Column) == nil {
+		return fmt.Errorf("filter column %q does not exist in table %s.%s", e.Column, metaSet.SchemaName, metaSet.TableName)
+	}
+	switch e.Op {
+	case "isnull", "in":
+		return nil
+	case "between":
+		if len(e.Values) != 2 {
+			return fmt.Errorf("filter operator %q requires two values", e.Op)
+		}
+		return nil
+	}
+	if _, ok := exprComparisons[e.Op]; !ok {
+		return fmt.Errorf("unknown filter operator %q", e.Op)
+	}
+	if len(e.Values) != 1 {
+		return fmt.Errorf("filter operator %q requires one value", e.Op)
+	}
+	return nil
+}
+
+// writeOn writes the condition and appends its parameter values to args.
+// The expression must be valid.
+func (e Expr) writeOn(b *strings.Builder, args []any) []any {
+	switch e.Op {
+	case "and", "or":
+		if len(e.Args) == 0 {
+			if e.Op == "and" {
+				b.WriteString("true")
+			} else {
+				b.WriteString("false")
+			}
+			return args
+		}
+		b.WriteRune('(')
+		for i, each := range e.Args {
+			if i > 0 {
+				b.WriteRune(' ')
+				b.WriteString(strings.ToUpper(e.Op))
+				b.WriteRune(' ')
+			}
+			args = each.writeOn(b, args)
+		}
+		b.WriteRune(')')
+		return args
+	case "not":
+		b.WriteString("NOT ")
+		b.WriteRune('(')
+		args = e.Args[0].writeOn(b, args)
+		b.WriteRune(')')
+		return args
+	}
+	if e.Op == "in" && len(e.Values) == 0 {
+		// nothing is in an empty list
+		b.WriteString("false")
+		return args
+	}
+	b.WriteString(quoteIdentifier(e.Column))
+	switch e.Op {
+	case "isnull":
+		b.WriteString(" IS NULL")
+	case "in":
+		b.WriteString(" IN (")
+		for i, each := range e.Values {
+			if i > 0 {
+				b.WriteRune(',')
+			}
+			args = append(args, each)
+			fmt.Fprintf(b, "$%d", len(args))
+		}
+		b.WriteRune(')')
+	case "between":
+		args = append(args, e.Values[0], e.Values[1])
+		fmt.Fprintf(b, " BETWEEN $%d AND $%d", len(args)-1, len(args))
+	default:
+		args = append(args, e.Values[0])
+		fmt.Fprintf(b, " %s $%d", exprComparisons[e.Op], len(args))
+	}
+	return args
+}
@@ -0,0 +1,96 @@
+package anyrow
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExprWriteOn(t *testing.T) {
+	e := And(
+		Eq("str", "shoe"),
+		Or(Lt("num", 1), Between("num", 10, 20)),
+		Not(IsNull("str")),
+		In("num", 1, 2),
+		ILike("str", "%O%"),
+	)
+	if err := e.validate(testMetaSet()); err != nil {
+		t.Fatal(err)
+	}
+	b := new(strings.Builder)
+	args := e.writeOn(b, nil)
+	if got, want := b.String(), `("str" = $1 AND ("num" < $2 OR "num" BETWEEN $3 AND $4) AND NOT ("str" IS NULL) AND "num" IN ($5,$6) AND "str" ILIKE $7)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := args, []any{"shoe", 1, 10, 20, 1, 2, "%O%"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestExprEmpty(t *testing.T) {
+	b := new(strings.Builder)
+	And(Or(), In("num")).writeOn(b, nil)
+	if got, want := b.String(), `(false AND false)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestExprValidate(t *testing.T) {
+	for _, each := range []Expr{
+		Eq(`str" = '' OR true --`, 1),
+		{Op: "drop", Column: "str"},
+		{Op: "eq", Column: "str"},
+		Not(Expr{Op: "between", Column: "num", Values: []any{1}}),
+	} {
+		if err := each.validate(testMetaSet()); err == nil {
+			t.Errorf("error expected for %v", each)
+		}
+	}
+}
+
+func TestParseExprJSON(t *testing.T) {
+	e := Or(Eq("num", 9066261786704621), Like("str", "s%"))
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := ParseExprJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := back.Args[0].Values[0], json.Number("9066261786704621"); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := back.Args[1].Column, "str"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFilterRecordsWhere(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	_, err := FilterRecords(ctx, conn, "testkey", "test", "num > 0 OR num < 0", FilterWhere(Eq("str", "shoe")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `SELECT "str","num" FROM public.test WHERE (num > 0 OR num < 0) AND "str" = $1 LIMIT 1000`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.args, []any{"shoe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFilterRecordsWhereUnknownColumn(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	_, err := FilterRecords(ctx, conn, "testkey", "test", "", FilterWhere(Eq("missing", 1)))
+	if err == nil {
+		t.Fatal("error expected")
+	}
+	if conn.sql != "" {
+		t.Errorf("unexpected query: %q", conn.sql)
+	}
+}
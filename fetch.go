@@ -2,14 +2,16 @@ package anyrow
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
-	"math"
+	"net"
+	"net/netip"
 	"strings"
+	"time"
 
 	"github.com/emicklei/anyrow/pb"
+	pgx "github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -17,33 +19,58 @@ import (
 var defaultFilterLimit = 1000
 
 func fetchValues(ctx context.Context, conn Querier, metaSet *pb.RowSet, filter fetchFilter, collector valueCollector) error {
-	// get values
+	dbrows, err := queryValues(ctx, conn, metaSet, filter)
+	if err != nil {
+		return err
+	}
+	return streamValues(ctx, dbrows, metaSet, collector, func() bool { return true })
+}
+
+// queryValues queries the columns of the metadata set for the rows matching the filter.
+func queryValues(ctx context.Context, conn Querier, metaSet *pb.RowSet, filter fetchFilter) (pgx.Rows, error) {
+	sql, args := selectStatement(metaSet, filter)
+	slog.Debug("fetchValues", "sql", sql, "params", args)
+	return conn.Query(ctx, sql, args...) // args can be empty
+}
+
+// selectStatement returns the SELECT statement and its parameter values for the columns of the metadata set.
+func selectStatement(metaSet *pb.RowSet, filter fetchFilter) (string, []any) {
 	qb := new(strings.Builder)
 	qb.WriteString("SELECT ")
 	for i, each := range metaSet.ColumnSchemas {
 		if i > 0 {
 			qb.WriteRune(',')
 		}
-		fmt.Fprintf(qb, "%q", each.Name)
+		filter.columnOn(qb, each)
 	}
 	qb.WriteString(" FROM ")
 	qb.WriteString(metaSet.SchemaName)
 	qb.WriteRune('.')
 	qb.WriteString(metaSet.TableName)
 	qb.WriteString(" WHERE ")
-	filter.whereOn(qb)
+	args := filter.whereOn(qb)
+	filter.orderOn(qb)
 	if !filter.pkv.hasValues() {
 		filter.limitOn(qb)
 	}
-	sql := qb.String()
-	slog.Debug("fetchValues", "sql", sql, "params", filter.pkv.parameterValues())
-	dbrows, err := conn.Query(ctx, qb.String(), filter.pkv.parameterValues()...) // parameterValues can be empty
-	if err != nil {
-		return err
-	}
+	return qb.String(), args
+}
+
+// collectValues reads all rows and passes each value to the collector.
+// The rows are closed when done.
+func collectValues(dbrows pgx.Rows, metaSet *pb.RowSet, collector valueCollector) error {
+	return streamValues(context.Background(), dbrows, metaSet, collector, func() bool { return true })
+}
+
+// streamValues reads rows and passes each value to the collector, calling rowDone after each row.
+// It stops reading when rowDone returns false or the context is done. The rows are closed when done.
+func streamValues(ctx context.Context, dbrows pgx.Rows, metaSet *pb.RowSet, collector valueCollector, rowDone func() bool) error {
 	defer dbrows.Close()
 
 	for dbrows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		all, err := dbrows.Values()
 		if err != nil {
 			var pgErr *pgconn.PgError
@@ -53,56 +80,144 @@ func fetchValues(ctx context.Context, conn Querier, metaSet *pb.RowSet, filter f
 			}
 			return err
 		}
+		shapeArrays(dbrows.FieldDescriptions(), dbrows.RawValues(), all)
 		collector.nextRow(len(all))
-		for i, each := range all {
-			if each == nil {
-				continue
+		if err := storeValues(metaSet, all, collector); err != nil {
+			return err
+		}
+		if !rowDone() {
+			return nil
+		}
+	}
+	return dbrows.Err()
+}
+
+// storeValues passes each value of a row to the collector.
+func storeValues(metaSet *pb.RowSet, all []any, collector valueCollector) error {
+	for i, each := range all {
+		if each == nil {
+			collector.storeNull(i)
+			continue
+		}
+		schema := metaSet.ColumnSchemas[i]
+		if codec, ok := codecFor(schema); ok {
+			if err := codec.Store(each, collectorWriter{collector: collector, index: i}); err != nil {
+				return fmt.Errorf("codec failed for column %q: %w", schema.Name, err)
 			}
-			switch each.(type) {
-			case string:
-				collector.storeString(i, each.(string))
-			case int64:
-				collector.storeInt64(i, each.(int64))
-			case int32:
-				collector.storeInt64(i, int64(each.(int32)))
-			case float64:
-				f := each.(float64)
-				tn := metaSet.ColumnSchemas[i].TypeName
-				if tn == "double precision" {
-					if f > math.MaxFloat32 {
-						collector.storeString(i, fmt.Sprintf("%f", f))
-					} else {
-						collector.storeFloat32(i, float32(f))
-					}
-					break
-				}
-				// check for integer like
-				if strings.Contains("integer bigint smallint", tn) {
-					fint, _ := math.Modf(f)
-					collector.storeInt64(i, int64(fint))
-					break
-				}
-				collector.storeFloat32(i, float32(f))
-			case map[string]any, []any:
-				collector.storeDefault(i, each)
-			case bool:
-				collector.storeBool(i, each.(bool))
-			case [16]uint8:
-				// handle as pgtype.UUID
-				collector.storeString(i, _UUIDToString(each.([16]uint8)))
-			case pgtype.Numeric:
-				// large numbers need to be quoted
-				data, _ := json.Marshal(each.(pgtype.Numeric))
-				collector.storeString(i, string(data))
-			default:
-				slog.Debug("[anyrow] handled as object", "value", each, "value.type", fmt.Sprintf("%T", each))
-				collector.storeDefault(i, each)
+			continue
+		}
+		if len(schema.AttributeSchemas) > 0 && schema.ElementTypeOid != 0 {
+			if err := storeCompositeArrayValue(collector, i, schema, each); err != nil {
+				return err
 			}
+			continue
+		}
+		if len(schema.AttributeSchemas) > 0 || schema.TypeOid == pgtype.RecordOID {
+			if err := storeCompositeValue(collector, i, schema, each); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := storeConverted(collector, i, schema.TypeOid, each); err != nil {
+			return fmt.Errorf("conversion failed for column %q: %w", schema.Name, err)
 		}
 	}
 	return nil
 }
 
+// storeConverted passes a value to the collector, converted depending on the data type of its column.
+func storeConverted(collector valueCollector, i int, typeOID uint32, each any) error {
+	if convert, ok := oidConverters[typeOID]; ok {
+		return convert(collector, i, each)
+	}
+	if elementOID, ok := arrayElementOID(typeOID); ok {
+		if values, ok := each.([]any); ok {
+			return storeArrayValue(collector, i, elementOID, values)
+		}
+	}
+	if elementOID, ok := rangeElementOID(typeOID); ok {
+		if value, ok := each.(pgtype.Range[any]); ok {
+			return storeRangeValue(collector, i, elementOID, value)
+		}
+	}
+	if rangeOID, ok := multirangeRangeOID(typeOID); ok {
+		if value, ok := each.(pgtype.Multirange[pgtype.Range[any]]); ok {
+			return storeMultirangeValue(collector, i, rangeOID, value)
+		}
+	}
+	return storeByType(collector, i, each)
+}
+
+// storeByType passes a value to the collector depending on its Go type.
+func storeByType(collector valueCollector, i int, each any) error {
+	switch each.(type) {
+	case string:
+		collector.storeString(i, each.(string))
+	case []byte:
+		collector.storeBytes(i, each.([]byte))
+	case int64:
+		collector.storeInt64(i, each.(int64))
+	case int32:
+		collector.storeInt64(i, int64(each.(int32)))
+	case int16:
+		collector.storeInt64(i, int64(each.(int16)))
+	case float32:
+		collector.storeFloat32(i, each.(float32))
+	case float64:
+		collector.storeFloat64(i, each.(float64))
+	case map[string]any, []any:
+		collector.storeDefault(i, each)
+	case bool:
+		collector.storeBool(i, each.(bool))
+	case [16]uint8:
+		// handle as pgtype.UUID
+		collector.storeString(i, _UUIDToString(each.([16]uint8)))
+	case pgtype.Numeric:
+		return storeNumeric(collector, i, each.(pgtype.Numeric))
+	case time.Time:
+		collector.storeTimestamp(i, each.(time.Time).UTC())
+	case pgtype.Time:
+		collector.storeTime(i, each.(pgtype.Time))
+	case pgtype.Interval:
+		collector.storeInterval(i, each.(pgtype.Interval))
+	case pgtype.InfinityModifier:
+		// infinity or -infinity of a date or timestamp
+		collector.storeString(i, each.(pgtype.InfinityModifier).String())
+	case netip.Prefix:
+		// inet or cidr
+		collector.storeString(i, each.(netip.Prefix).String())
+	case net.HardwareAddr:
+		// macaddr or macaddr8
+		collector.storeString(i, each.(net.HardwareAddr).String())
+	default:
+		if coordinates, ok := geometricCoordinates(each); ok {
+			collector.storeArray(i, coordinates)
+			return nil
+		}
+		slog.Debug("[anyrow] handled as object", "value", each, "value.type", fmt.Sprintf("%T", each))
+		collector.storeDefault(i, each)
+	}
+	return nil
+}
+
+// quoteIdentifier returns the name as a double-quoted SQL identifier.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// unquoteIdentifier returns the name of a double-quoted SQL identifier.
+// Any other text, such as an unquoted name or an expression, is returned as is.
+func unquoteIdentifier(text string) string {
+	if len(text) < 2 || text[0] != '"' || text[len(text)-1] != '"' {
+		return text
+	}
+	inner := text[1 : len(text)-1]
+	if strings.Contains(strings.ReplaceAll(inner, `""`, ""), `"`) {
+		return text
+	}
+	return strings.ReplaceAll(inner, `""`, `"`)
+}
+
 // _UUIDToString returns format xxxx-yyyy-zzzz-rrrr-tttt
 func _UUIDToString(src [16]uint8) string {
 	return fmt.Sprintf("%x-%x-%x-%x-%x", src[0:4], src[4:6], src[6:8], src[8:10], src[10:16])
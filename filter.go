@@ -2,8 +2,12 @@ package anyrow
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type filterOption func(f fetchFilter) fetchFilter
@@ -12,36 +16,237 @@ type fetchFilter struct {
 	pkv   PrimaryKeysAndValues
 	where string
 	limit int
+	expr  *Expr
+	// projection
+	columns        []string
+	excludeColumns []string
+	orderBy        []columnOrder
+	// bytea columns: omitted or capped to a maximum number of bytes if > 0
+	omitBytes bool
+	maxBytes  int
+	// keyset pagination: rows ordered by the columns and after the values
+	after []any
+}
+
+type columnOrder struct {
+	column     string
+	descending bool
+}
+
+// FilterOrderBy adds a column to sort the rows by, descending if desc is true.
+func FilterOrderBy(column string, desc bool) filterOption {
+	return func(f fetchFilter) fetchFilter {
+		f.orderBy = append(f.orderBy, columnOrder{column: column, descending: desc})
+		return f
+	}
+}
+
+// FilterColumns selects the columns to fetch, in the given order. Without this option, all columns are fetched.
+func FilterColumns(names ...string) filterOption {
+	return func(f fetchFilter) fetchFilter {
+		f.columns = append(f.columns, names...)
+		return f
+	}
+}
+
+// FilterExcludeColumns excludes columns from being fetched.
+func FilterExcludeColumns(names ...string) filterOption {
+	return func(f fetchFilter) fetchFilter {
+		f.excludeColumns = append(f.excludeColumns, names...)
+		return f
+	}
+}
+
+// FilterOmitBytes excludes all bytea columns from being fetched.
+func FilterOmitBytes() filterOption {
+	return func(f fetchFilter) fetchFilter {
+		f.omitBytes = true
+		return f
+	}
+}
+
+// FilterMaxBytes fetches at most the first max bytes of the values of bytea columns.
+func FilterMaxBytes(max int) filterOption {
+	return func(f fetchFilter) fetchFilter {
+		f.maxBytes = max
+		return f
+	}
+}
+
+// newFetchFilter returns a filter for a WHERE clause and a limit, with the options applied.
+func newFetchFilter(where string, limit int, options []filterOption) fetchFilter {
+	filter := fetchFilter{
+		where: where,
+		limit: limit,
+	}
+	for _, each := range options {
+		filter = each(filter)
+	}
+	return filter
+}
+
+// FilterWhere adds a filter expression to the condition.
+// Its column names are validated and its values are passed as parameters.
+func FilterWhere(expr Expr) filterOption {
+	return func(f fetchFilter) fetchFilter {
+		f.expr = &expr
+		return f
+	}
+}
+
+// whereOn writes the condition and returns the values of its parameters.
+func (f fetchFilter) whereOn(b *strings.Builder) []any {
+	args := slices.Clone(f.pkv.parameterValues())
+	conditions := []string{}
+	if f.pkv.column != "" || len(f.pkv.pairs) > 0 {
+		pb := new(strings.Builder)
+		f.keysOn(pb)
+		conditions = append(conditions, pb.String())
+	} else if f.where != "" {
+		conditions = append(conditions, f.where)
+	}
+	if f.expr != nil {
+		eb := new(strings.Builder)
+		args = f.expr.writeOn(eb, args)
+		conditions = append(conditions, eb.String())
+	}
+	if len(f.after) > 0 {
+		kb := new(strings.Builder)
+		args = f.afterOn(kb, args)
+		conditions = append(conditions, kb.String())
+	}
+	if len(conditions) == 0 {
+		// all empty
+		b.WriteString("true")
+		return args
+	}
+	if len(conditions) > 1 && conditions[0] == f.where {
+		// the custom WHERE condition can have operators with lower precedence than AND
+		conditions[0] = "(" + f.where + ")"
+	}
+	b.WriteString(strings.Join(conditions, " AND "))
+	return args
+}
+
+// validate returns an error if the filter refers to columns that are not part of the table.
+func (f fetchFilter) validate(metaSet *pb.RowSet) error {
+	for _, each := range f.orderBy {
+		if columnSchemaNamed(metaSet, each.column) == nil {
+			return fmt.Errorf("order column %q does not exist in table %s.%s", each.column, metaSet.SchemaName, metaSet.TableName)
+		}
+	}
+	if f.expr != nil {
+		return f.expr.validate(metaSet)
+	}
+	return nil
+}
+
+// project validates the filter and returns the metadata set with only the columns selected by the filter.
+func (f fetchFilter) project(metaSet *pb.RowSet) (*pb.RowSet, error) {
+	if err := f.validate(metaSet); err != nil {
+		return nil, err
+	}
+	if len(f.columns) == 0 && len(f.excludeColumns) == 0 && !f.omitBytes {
+		return metaSet, nil
+	}
+	for _, each := range append(slices.Clone(f.columns), f.excludeColumns...) {
+		if columnSchemaNamed(metaSet, each) == nil {
+			return nil, fmt.Errorf("column %q does not exist in table %s.%s", each, metaSet.SchemaName, metaSet.TableName)
+		}
+	}
+	set := &pb.RowSet{
+		SchemaName: metaSet.SchemaName,
+		TableName:  metaSet.TableName,
+	}
+	selected := metaSet.ColumnSchemas
+	if len(f.columns) > 0 {
+		selected = nil
+		for _, each := range f.columns {
+			selected = append(selected, columnSchemaNamed(metaSet, each))
+		}
+	}
+	for _, each := range selected {
+		if f.omitBytes && each.TypeOid == pgtype.ByteaOID {
+			continue
+		}
+		if !slices.Contains(f.excludeColumns, each.Name) {
+			set.ColumnSchemas = append(set.ColumnSchemas, each)
+		}
+	}
+	if len(set.ColumnSchemas) == 0 {
+		return nil, fmt.Errorf("no columns selected of table %s.%s", metaSet.SchemaName, metaSet.TableName)
+	}
+	return set, nil
 }
 
-func (f fetchFilter) whereOn(b *strings.Builder) {
+// columnOn writes the select expression of a column.
+func (f fetchFilter) columnOn(b *strings.Builder, schema *pb.ColumnSchema) {
+	name := quoteIdentifier(schema.Name)
+	if f.maxBytes > 0 && schema.TypeOid == pgtype.ByteaOID {
+		fmt.Fprintf(b, "substring(%s from 1 for %d) AS %s", name, f.maxBytes, name)
+		return
+	}
+	if schema.TypeOid == moneyOID {
+		fmt.Fprintf(b, "%s::numeric AS %s", name, name)
+		return
+	}
+	b.WriteString(name)
+}
+
+// afterOn writes the keyset condition and appends its parameter values to args.
+func (f fetchFilter) afterOn(b *strings.Builder, args []any) []any {
+	b.WriteRune('(')
+	for i, each := range f.orderBy {
+		if i > 0 {
+			b.WriteRune(',')
+		}
+		b.WriteString(quoteIdentifier(each.column))
+	}
+	b.WriteString(") > (")
+	for i, each := range f.after {
+		if i > 0 {
+			b.WriteRune(',')
+		}
+		args = append(args, each)
+		fmt.Fprintf(b, "$%d", len(args))
+	}
+	b.WriteRune(')')
+	return args
+}
+
+func (f fetchFilter) orderOn(b *strings.Builder) {
+	for i, each := range f.orderBy {
+		if i == 0 {
+			b.WriteString(" ORDER BY ")
+		} else {
+			b.WriteRune(',')
+		}
+		b.WriteString(quoteIdentifier(each.column))
+		if each.descending {
+			b.WriteString(" DESC")
+		}
+	}
+}
+
+func (f fetchFilter) keysOn(b *strings.Builder) {
 	// either one key with one or more values
 	if f.pkv.column != "" {
 		b.WriteString(f.pkv.column)
 		fmt.Fprintf(b, " IN (%s)", composeQueryParams(len(f.pkv.values)))
 		return
 	}
-	// or multiple keys with one value or more values
-	if len(f.pkv.pairs) > 1 {
-		// chain of ANDs:  (p1=v1 and p2=v2)
-		b.WriteRune('(')
-		p := 1
-		for i, each := range f.pkv.pairs {
-			if i > 0 {
-				b.WriteString(" AND ")
-			}
-			fmt.Fprintf(b, "%s=$%d", each.Column, p)
-			p++
+	// or one or more keys with one value each
+	// chain of ANDs:  (p1=v1 and p2=v2)
+	b.WriteRune('(')
+	p := 1
+	for i, each := range f.pkv.pairs {
+		if i > 0 {
+			b.WriteString(" AND ")
 		}
-		b.WriteRune(')')
-		return
-	}
-	if f.where != "" {
-		b.WriteString(f.where)
-		return
+		fmt.Fprintf(b, "%s=$%d", each.Column, p)
+		p++
 	}
-	// both empty
-	b.WriteString("true")
+	b.WriteRune(')')
 }
 
 func (f fetchFilter) limitOn(b *strings.Builder) {
@@ -3,6 +3,9 @@ package anyrow
 import (
 	"strings"
 	"testing"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func TestFetchFilter_WhereOn(t *testing.T) {
@@ -27,6 +30,14 @@ func TestFetchFilter_WhereOn(t *testing.T) {
 		t.Errorf("unexpected parameter values: %v", pvs)
 	}
 
+	// Test for a single key with one value
+	b.Reset()
+	f = fetchFilter{pkv: NewPrimaryKeysAndValues([]PrimaryKeyAndValue{{"name", "Bob"}})}
+	f.whereOn(&b)
+	if b.String() != "(name=$1)" {
+		t.Errorf("unexpected query: %q", b.String())
+	}
+
 	// Test for a custom WHERE condition
 	b.Reset()
 	f = fetchFilter{where: "created_at > '2021-01-01'"}
@@ -37,9 +48,66 @@ func TestFetchFilter_WhereOn(t *testing.T) {
 
 	// Test for both empty columns and WHERE condition
 	b.Reset()
-	f = fetchFilter{PrimaryKeysAndValues{}, "", 0}
+	f = fetchFilter{pkv: PrimaryKeysAndValues{}, where: "", limit: 0}
 	f.whereOn(&b)
 	if b.String() != "true" {
 		t.Errorf("unexpected query: %q", b.String())
 	}
 }
+
+func TestFetchFilter_Project(t *testing.T) {
+	f := newFetchFilter("", 0, []filterOption{FilterColumns("num", "str"), FilterExcludeColumns("str")})
+	set, err := f.project(testMetaSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(set.ColumnSchemas), 1; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := set.ColumnSchemas[0].Name, "num"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	// Test for unknown columns
+	for _, each := range []filterOption{FilterColumns("missing"), FilterExcludeColumns("missing"), FilterOrderBy("missing", false)} {
+		f = newFetchFilter("", 0, []filterOption{each})
+		if _, err := f.project(testMetaSet()); err == nil {
+			t.Error("error expected")
+		}
+	}
+
+	// Test for nothing selected
+	f = newFetchFilter("", 0, []filterOption{FilterExcludeColumns("num", "str")})
+	if _, err := f.project(testMetaSet()); err == nil {
+		t.Error("error expected")
+	}
+}
+
+func TestFetchFilter_Bytes(t *testing.T) {
+	metaSet := testMetaSet()
+	metaSet.ColumnSchemas = append(metaSet.ColumnSchemas, &pb.ColumnSchema{Name: "data", TypeName: "bytea", TypeOid: pgtype.ByteaOID})
+
+	f := newFetchFilter("", 0, []filterOption{FilterOmitBytes()})
+	set, err := f.project(metaSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(set.ColumnSchemas), 2; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	f = newFetchFilter("", 0, []filterOption{FilterMaxBytes(1024)})
+	sql, _ := selectStatement(metaSet, f)
+	if got, want := sql, `SELECT "str","num",substring("data" from 1 for 1024) AS "data" FROM public.test WHERE true`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFetchFilter_Money(t *testing.T) {
+	metaSet := testMetaSet()
+	metaSet.ColumnSchemas = append(metaSet.ColumnSchemas, &pb.ColumnSchema{Name: "price", TypeName: "money", TypeOid: moneyOID})
+	sql, _ := selectStatement(metaSet, newFetchFilter("", 0, nil))
+	if got, want := sql, `SELECT "str","num","price"::numeric AS "price" FROM public.test WHERE true`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
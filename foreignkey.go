@@ -0,0 +1,168 @@
+package anyrow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+// referentialActions maps the action codes of pg_constraint to their SQL names.
+var referentialActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// FetchForeignKeys returns the foreign keys of a table (outgoing) and those of other tables that reference it (incoming).
+// A foreign key of a table that references itself is both outgoing and incoming.
+func FetchForeignKeys(ctx context.Context, conn Querier, tableName string) (outgoing, incoming []*pb.ForeignKey, err error) {
+	return getForeignKeys(ctx, conn, tableName)
+}
+
+func getForeignKeys(ctx context.Context, conn Querier, tableName string) (outgoing, incoming []*pb.ForeignKey, err error) {
+	schema, tableName := splitTableName(tableName)
+	qualifiedTableName := fmt.Sprintf("%s.%s", schema, tableName)
+	query := `
+SELECT c.conname, ns.nspname, cl.relname,
+	ARRAY(
+		SELECT a.attname
+		FROM unnest(c.conkey) WITH ORDINALITY AS k(attnum, n)
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+		ORDER BY k.n
+	),
+	fns.nspname, fcl.relname,
+	ARRAY(
+		SELECT a.attname
+		FROM unnest(c.confkey) WITH ORDINALITY AS k(attnum, n)
+		JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.attnum
+		ORDER BY k.n
+	),
+	c.confdeltype::text, c.confupdtype::text,
+	c.conrelid = CAST($1 as regclass) AS isOutgoing,
+	c.confrelid = CAST($1 as regclass) AS isIncoming
+FROM pg_constraint c
+JOIN pg_class cl ON cl.oid = c.conrelid
+JOIN pg_namespace ns ON ns.oid = cl.relnamespace
+JOIN pg_class fcl ON fcl.oid = c.confrelid
+JOIN pg_namespace fns ON fns.oid = fcl.relnamespace
+WHERE c.contype = 'f'
+  AND (c.conrelid = CAST($1 as regclass) OR c.confrelid = CAST($1 as regclass))
+ORDER BY c.conname;
+`
+	rows, err := conn.Query(ctx, query, qualifiedTableName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getForeignKeys failed: %w, table:%s, schema:%s", err, tableName, schema)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		fk := new(pb.ForeignKey)
+		var onDelete, onUpdate string
+		var isOutgoing, isIncoming bool
+		if err := rows.Scan(&fk.Name, &fk.SchemaName, &fk.TableName, &fk.Columns,
+			&fk.ReferencedSchemaName, &fk.ReferencedTableName, &fk.ReferencedColumns,
+			&onDelete, &onUpdate, &isOutgoing, &isIncoming); err != nil {
+			return nil, nil, fmt.Errorf("getForeignKeys.scan failed: %w, table:%s, schema:%s", err, tableName, schema)
+		}
+		fk.OnDelete = referentialActions[onDelete]
+		fk.OnUpdate = referentialActions[onUpdate]
+		if isOutgoing {
+			outgoing = append(outgoing, fk)
+		}
+		if isIncoming {
+			incoming = append(incoming, fk)
+		}
+	}
+	return outgoing, incoming, rows.Err()
+}
+
+// FetchReferenced returns the rows that a record of a table references by its foreign key with the given name, the parent rows.
+// The metadata of the referenced table is cached by its qualified name.
+// Options can filter, order and select the columns of the referenced rows.
+func FetchReferenced(ctx context.Context, conn Querier, metadataCacheKey, tableName string, record Record, foreignKeyName string, options ...filterOption) ([]Record, error) {
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return nil, err
+	}
+	outgoing, _, err := getForeignKeys(ctx, conn, qualifiedName(set.SchemaName, set.TableName))
+	if err != nil {
+		return nil, err
+	}
+	fk := foreignKeyNamed(outgoing, foreignKeyName)
+	if fk == nil {
+		return nil, fmt.Errorf("foreign key %q of table %s.%s does not exist", foreignKeyName, set.SchemaName, set.TableName)
+	}
+	return fetchRelated(ctx, conn, qualifiedName(fk.ReferencedSchemaName, fk.ReferencedTableName), fk.ReferencedColumns, fk.Columns, record, options)
+}
+
+// FetchReferencing returns the rows that reference a record of a table by the foreign key with the given name, the child rows.
+// For a foreign key of a table that references itself, such as employees by their manager, the rows are of the same table.
+// The metadata of the referencing table is cached by its qualified name.
+// Options can filter, order and select the columns of the referencing rows.
+func FetchReferencing(ctx context.Context, conn Querier, metadataCacheKey, tableName string, record Record, foreignKeyName string, options ...filterOption) ([]Record, error) {
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return nil, err
+	}
+	_, incoming, err := getForeignKeys(ctx, conn, qualifiedName(set.SchemaName, set.TableName))
+	if err != nil {
+		return nil, err
+	}
+	fk := foreignKeyNamed(incoming, foreignKeyName)
+	if fk == nil {
+		return nil, fmt.Errorf("foreign key %q referencing table %s.%s does not exist", foreignKeyName, set.SchemaName, set.TableName)
+	}
+	return fetchRelated(ctx, conn, qualifiedName(fk.SchemaName, fk.TableName), fk.Columns, fk.ReferencedColumns, record, options)
+}
+
+// fetchRelated returns the rows of a table for which the columns are equal to the values of the record columns.
+// If a record value is NULL then no rows are related.
+func fetchRelated(ctx context.Context, conn Querier, tableName string, columns, recordColumns []string, record Record, options []filterOption) ([]Record, error) {
+	set, err := cachedMetadata(ctx, conn, tableName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	conditions := []Expr{}
+	for i, each := range recordColumns {
+		value, ok := record[each]
+		if !ok {
+			return nil, fmt.Errorf("record has no value for column %q", each)
+		}
+		if value == nil {
+			return []Record{}, nil
+		}
+		conditions = append(conditions, Eq(columns[i], value))
+	}
+	filter := newFetchFilter("", 0, options)
+	if filter.expr != nil {
+		conditions = append(conditions, *filter.expr)
+	}
+	where := And(conditions...)
+	filter.expr = &where
+	set, err = filter.project(set)
+	if err != nil {
+		return nil, err
+	}
+	collector := &objectCollector{
+		set: set,
+	}
+	err = fetchValues(ctx, conn, set, filter, collector)
+	return collector.list, err
+}
+
+// foreignKeyNamed returns the foreign key with the given name or nil if absent.
+func foreignKeyNamed(list []*pb.ForeignKey, name string) *pb.ForeignKey {
+	for _, each := range list {
+		if each.Name == name {
+			return each
+		}
+	}
+	return nil
+}
+
+// qualifiedName returns the name of a table qualified by its schema.
+func qualifiedName(schema, tableName string) string {
+	return schema + "." + tableName
+}
@@ -0,0 +1,144 @@
+package anyrow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+func setupOrderKeys() {
+	metaCache.Set("testorderkey", &pb.RowSet{SchemaName: "public", TableName: "orders", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "customer_id", TypeName: "bigint", IsNullable: true},
+	}}, 0)
+	metaCache.Set("public.customers", &pb.RowSet{SchemaName: "public", TableName: "customers", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "name", TypeName: "text"},
+	}}, 0)
+}
+
+// orderCustomerKey returns the values of a row of the foreign key query.
+func orderCustomerKey(isOutgoing, isIncoming bool) []any {
+	return []any{"orders_customer_fk", "public", "orders", []string{"customer_id"}, "public", "customers", []string{"id"}, "c", "a", isOutgoing, isIncoming}
+}
+
+func TestFetchForeignKeys(t *testing.T) {
+	conn := &mockQuerier{values: orderCustomerKey(true, false)}
+	outgoing, incoming, err := FetchForeignKeys(context.Background(), conn, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.args, []any{"public.orders"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(outgoing), 1; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(incoming), 0; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	fk := outgoing[0]
+	if got, want := fk.ReferencedTableName, "customers"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := fk.OnDelete, "CASCADE"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := fk.OnUpdate, "NO ACTION"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFetchReferenced(t *testing.T) {
+	setupOrderKeys()
+	conn := &mockQuerier{results: [][][]any{{orderCustomerKey(true, false)}, {{int64(7), "acme"}}}}
+	order := Record{"id": int64(1), "customer_id": int64(7)}
+	rows, err := FetchReferenced(context.Background(), conn, "testorderkey", "orders", order, "orders_customer_fk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `SELECT "id","name" FROM public.customers WHERE ("id" = $1)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.args, []any{int64(7)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows[0]["name"], "acme"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFetchReferencing(t *testing.T) {
+	setupOrderKeys()
+	metaCache.Set("public.orders", &pb.RowSet{SchemaName: "public", TableName: "orders", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "customer_id", TypeName: "bigint", IsNullable: true},
+	}}, 0)
+	conn := &mockQuerier{results: [][][]any{{orderCustomerKey(false, true)}, {{int64(1), int64(7)}}}}
+	customer := Record{"id": int64(7), "name": "acme"}
+	_, err := FetchReferencing(context.Background(), conn, "public.customers", "customers", customer, "orders_customer_fk", FilterOrderBy("id", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `SELECT "id","customer_id" FROM public.orders WHERE ("customer_id" = $1) ORDER BY "id" DESC`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFetchReferencedNullOrUnknown(t *testing.T) {
+	setupOrderKeys()
+	conn := &mockQuerier{values: orderCustomerKey(true, false)}
+	rows, err := FetchReferenced(context.Background(), conn, "testorderkey", "orders", Record{"id": int64(1), "customer_id": nil}, "orders_customer_fk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(rows), 0; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(conn.sqls), 1; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if _, err := FetchReferenced(context.Background(), conn, "testorderkey", "orders", Record{}, "missing_fk"); err == nil {
+		t.Error("error expected")
+	}
+}
+
+func TestFetchReferencedSelfReference(t *testing.T) {
+	metaCache.Set("testemployeekey", &pb.RowSet{SchemaName: "public", TableName: "employees", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "manager_id", TypeName: "bigint", IsNullable: true},
+	}}, 0)
+	metaCache.Set("public.employees", &pb.RowSet{SchemaName: "public", TableName: "employees", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "manager_id", TypeName: "bigint", IsNullable: true},
+	}}, 0)
+	managerKey := []any{"employees_manager_fk", "public", "employees", []string{"manager_id"}, "public", "employees", []string{"id"}, "a", "a", true, true}
+	employee := Record{"id": int64(2), "manager_id": int64(1)}
+
+	conn := &mockQuerier{results: [][][]any{{managerKey}, {{int64(1), nil}}}}
+	if _, err := FetchReferenced(context.Background(), conn, "testemployeekey", "employees", employee, "employees_manager_fk"); err != nil {
+		t.Fatal(err)
+	}
+	// the manager
+	if got, want := conn.args, []any{int64(1)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	conn = &mockQuerier{results: [][][]any{{managerKey}, {{int64(3), int64(2)}}}}
+	rows, err := FetchReferencing(context.Background(), conn, "testemployeekey", "employees", employee, "employees_manager_fk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the employees managed
+	if got, want := conn.sql, `SELECT "id","manager_id" FROM public.employees WHERE ("manager_id" = $1)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.args, []any{int64(2)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows[0]["id"], int64(3); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
@@ -0,0 +1,130 @@
+package anyrow
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+// InsertRecords inserts each Record as a new row of a table and returns the primary key values of the inserted rows.
+// Keys of a Record that are not columns of the table are ignored, unless the WriteStrict option is given.
+// Each Record is inserted using a separate statement; use a pgx.Tx as conn to make all inserts atomic.
+func InsertRecords(ctx context.Context, conn Querier, metadataCacheKey, tableName string, records []Record, options ...writeOption) ([]Record, error) {
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return insertRecords(ctx, conn, set, records, newWriteConfig(options))
+}
+
+// InsertRowSet inserts all rows of a RowSet into its table and returns the primary key values of the inserted rows.
+// The column schemas of the table are taken from the cache, not from the RowSet.
+// Each column value is passed as a parameter that matches the data type of its column,
+// such that a RowSet returned by FetchRowSet can be inserted as is.
+// Values of generated columns are not inserted; values of identity columns are, overriding those of the database.
+func InsertRowSet(ctx context.Context, conn Querier, metadataCacheKey string, set *pb.RowSet, options ...writeOption) ([]Record, error) {
+	metaSet, err := cachedMetadata(ctx, conn, metadataCacheKey, rowSetTableName(set))
+	if err != nil {
+		return nil, err
+	}
+	cfg := newWriteConfig(options)
+	cfg.overriding = true
+	return insertRecords(ctx, conn, metaSet, rowSetRecords(metaSet, set), cfg)
+}
+
+// rowSetRecords returns the rows of a RowSet as Records of parameter values for the columns of the metadata set.
+// Values of columns that are not part of the metadata set are kept as Go values.
+// Values of generated columns are left out because the database computes them.
+func rowSetRecords(metaSet, set *pb.RowSet) []Record {
+	records := make([]Record, len(set.Rows))
+	for r, row := range set.Rows {
+		record := make(Record, len(row.Columns))
+		for c, cell := range row.Columns {
+			name := set.ColumnSchemas[c].Name
+			if schema := columnSchemaNamed(metaSet, name); schema != nil {
+				if schema.IsGenerated {
+					continue
+				}
+				record[name] = parameterValue(schema, cell)
+			} else {
+				record[name] = cell.Value()
+			}
+		}
+		records[r] = record
+	}
+	return records
+}
+
+// rowSetTableName returns the table name of the set, qualified with its schema if known.
+func rowSetTableName(set *pb.RowSet) string {
+	if set.SchemaName != "" {
+		return set.SchemaName + "." + set.TableName
+	}
+	return set.TableName
+}
+
+func insertRecords(ctx context.Context, conn Querier, metaSet *pb.RowSet, records []Record, cfg writeConfig) ([]Record, error) {
+	keys := &objectCollector{
+		set: primaryKeySet(metaSet),
+	}
+	for _, each := range records {
+		if err := cfg.checkColumns(metaSet, each); err != nil {
+			return keys.list, err
+		}
+		if err := checkEnumValues(metaSet, each); err != nil {
+			return keys.list, err
+		}
+		sql, args := insertStatement(metaSet, keys.set, each, cfg)
+		slog.Debug("insertRecords", "sql", sql, "params", args)
+		dbrows, err := conn.Query(ctx, sql, args...)
+		if err != nil {
+			return keys.list, err
+		}
+		if err := collectValues(dbrows, keys.set, keys); err != nil {
+			return keys.list, err
+		}
+	}
+	return keys.list, nil
+}
+
+// insertStatement returns the INSERT statement and its parameter values for a record.
+// Columns are listed in the order of the metadata set.
+func insertStatement(metaSet, keySet *pb.RowSet, record Record, cfg writeConfig) (string, []any) {
+	qb := new(strings.Builder)
+	qb.WriteString("INSERT INTO ")
+	qb.WriteString(metaSet.SchemaName)
+	qb.WriteRune('.')
+	qb.WriteString(metaSet.TableName)
+	columns := []string{}
+	args := []any{}
+	identity := false
+	for _, each := range metaSet.ColumnSchemas {
+		value, ok := record[each.Name]
+		if !ok {
+			continue
+		}
+		columns = append(columns, quoteIdentifier(each.Name))
+		args = append(args, value)
+		identity = identity || (each.IsIdentity && each.IdentityGeneration == "ALWAYS")
+	}
+	if len(columns) == 0 {
+		qb.WriteString(" DEFAULT VALUES")
+	} else {
+		qb.WriteString(" (")
+		qb.WriteString(strings.Join(columns, ","))
+		qb.WriteRune(')')
+		if identity && cfg.overriding {
+			qb.WriteString(" OVERRIDING SYSTEM VALUE")
+		}
+		qb.WriteString(" VALUES (")
+		qb.WriteString(composeQueryParams(len(args)))
+		qb.WriteRune(')')
+	}
+	if cfg.upsert {
+		cfg.onConflictOn(qb, metaSet, record)
+	}
+	returningOn(qb, keySet)
+	return qb.String(), args
+}
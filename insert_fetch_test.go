@@ -2,9 +2,13 @@ package anyrow
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/emicklei/anyrow/pb"
 	"github.com/google/uuid"
 )
 
@@ -39,6 +43,540 @@ func TestInsertThenFetch(t *testing.T) {
 	t.Log(rows[0]["id"])
 	ftDoublePrecision := rows[0]["tdoubleprecision"]
 	t.Logf("%v->%v (%T)", tDoublePrecision, ftDoublePrecision, ftDoublePrecision)
+	if got, want := ftDoublePrecision, tDoublePrecision; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
 	ftNumeric := rows[0]["tnumeric"]
 	t.Logf("%v->%v (%T)", tNumeric, ftNumeric, ftNumeric)
+	if got, want := ftNumeric, json.Number("9066261786704621"); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertThenFetchTemporal(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id := uuid.New()
+	amsterdam := time.FixedZone("CET", 3600)
+	tTimestamptz := time.Date(2024, 2, 29, 13, 14, 15, 123456000, amsterdam)
+	tTimestamp := time.Date(2024, 2, 29, 13, 14, 15, 0, time.UTC)
+	tDate := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	_, err := testConnect.Exec(ctx, `insert into fieldbags (id,tdate,ttimestamp,ttimestamptz,ttime,tinterval)
+		values ($1,$2,$3,$4,'13:14:15.5','1 year 2 months 3 days 04:05:06.5')`, id, tDate, tTimestamp, tTimestamptz)
+	check(t, err)
+
+	pkvs := NewPrimaryKeyAndValues("id", id)
+	rows, err := FetchRecords(ctx, testConnect, "cache.temporal", "fieldbags", pkvs)
+	check(t, err)
+	if got, want := rows[0]["ttimestamptz"], tTimestamptz.UTC(); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows[0]["ttimestamp"], tTimestamp; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows[0]["tdate"], tDate; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows[0]["ttime"].(*pb.TimeOfDay).Format(), "13:14:15.5"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	// a Record with temporal values can be written as is
+	_, err = UpdateRecord(ctx, testConnect, "cache.temporal", "fieldbags", NewPrimaryKeyAndValues("id", id),
+		Record{"ttime": rows[0]["ttime"], "tinterval": rows[0]["tinterval"]}, WriteNonKeyColumns())
+	check(t, err)
+
+	set, err := FetchRowSet(ctx, testConnect, "cache.temporal", "fieldbags", pkvs)
+	check(t, err)
+	mp := set.RowMap(0)
+	if got, want := mp["tdate"], tDate; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := mp["ttime"].(*pb.TimeOfDay).Format(), "13:14:15.5"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := mp["tinterval"].(*pb.Interval).Format(), "P1Y2M3DT4H5M6.5S"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertThenFetchArrays(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id := uuid.New()
+	_, err := testConnect.Exec(ctx, `insert into fieldbags (id,tintarray,ttextarray,tuuidarray,tnumericarray,tmatrix)
+		values ($1,'{1,NULL,3}','{"a","b c"}',ARRAY[$1::uuid],'{12.50}','{{1,2},{3,4}}')`, id)
+	check(t, err)
+
+	pkvs := NewPrimaryKeyAndValues("id", id)
+	rows, err := FetchRecords(ctx, testConnect, "cache.arrays", "fieldbags", pkvs)
+	check(t, err)
+	want := Record{
+		"tintarray":     []any{int64(1), nil, int64(3)},
+		"ttextarray":    []any{"a", "b c"},
+		"tuuidarray":    []any{id.String()},
+		"tnumericarray": []any{json.Number("12.50")},
+		"tmatrix":       []any{[]any{int64(1), int64(2)}, []any{int64(3), int64(4)}},
+	}
+	for k, v := range want {
+		if got, want := rows[0][k], v; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got [%v:%T] want [%v:%T]", k, got, got, want, want)
+		}
+	}
+
+	set, err := FetchRowSet(ctx, testConnect, "cache.arrays", "fieldbags", pkvs)
+	check(t, err)
+	if got, want := set.RowMap(0)["tmatrix"], want["tmatrix"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertThenFetchNull(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id := uuid.New()
+	_, err := testConnect.Exec(ctx, `insert into fieldbags (id) values ($1)`, id)
+	check(t, err)
+
+	pkvs := NewPrimaryKeyAndValues("id", id)
+	rows, err := FetchRecords(ctx, testConnect, "cache.null", "fieldbags", pkvs)
+	check(t, err)
+	if v, ok := rows[0]["ttext"]; !ok || v != nil {
+		t.Errorf("got [%v:%v] want [nil:true]", v, ok)
+	}
+	set, err := FetchRowSet(ctx, testConnect, "cache.null", "fieldbags", pkvs)
+	check(t, err)
+	if v, ok := set.RowMap(0)["tinteger"]; !ok || v != nil {
+		t.Errorf("got [%v:%v] want [nil:true]", v, ok)
+	}
+}
+
+func TestInsertThenFetchBytes(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id := uuid.New()
+	data := []byte{0, 1, 2, 3, 254, 255}
+	_, err := testConnect.Exec(ctx, `insert into fieldbags (id,tbytea) values ($1,$2)`, id, data)
+	check(t, err)
+
+	pkvs := NewPrimaryKeyAndValues("id", id)
+	rows, err := FetchRecords(ctx, testConnect, "cache.bytes", "fieldbags", pkvs)
+	check(t, err)
+	if got, want := rows[0]["tbytea"], data; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	rows, err = FetchRecords(ctx, testConnect, "cache.bytes", "fieldbags", pkvs, FilterMaxBytes(2))
+	check(t, err)
+	if got, want := rows[0]["tbytea"], data[:2]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	set, err := FetchRowSet(ctx, testConnect, "cache.bytes", "fieldbags", pkvs, FilterOmitBytes())
+	check(t, err)
+	if _, ok := set.RowMap(0)["tbytea"]; ok {
+		t.Error("bytea column not expected")
+	}
+}
+
+func TestInsertThenFetchSpecialTypes(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id := uuid.New()
+	_, err := testConnect.Exec(ctx, `insert into fieldbags (id,tinet,tcidr,tmacaddr,tint4range,tint4ranges,tint4multirange,ttstzrange,tdaterange,tpoint,tbox,tpolygon,tmoney)
+		values ($1,'192.168.0.1','10.0.0.0/8','08:00:2b:01:02:03','[1,10)','{"[1,10)",NULL}','{[1,3),[5,7)}','[2024-01-02 10:00:00+01,)','empty','(1,2)','((1,2),(3,4))','((0,0),(0,1),(1,0))','12.34')`, id)
+	check(t, err)
+
+	pkvs := NewPrimaryKeyAndValues("id", id)
+	rows, err := FetchRecords(ctx, testConnect, "cache.special", "fieldbags", pkvs)
+	check(t, err)
+	want := Record{
+		"tinet":       "192.168.0.1",
+		"tcidr":       "10.0.0.0/8",
+		"tmacaddr":    "08:00:2b:01:02:03",
+		"tint4range":  map[string]any{"lower": int64(1), "upper": int64(10), "bounds": "[)"},
+		"tint4ranges": []any{map[string]any{"lower": int64(1), "upper": int64(10), "bounds": "[)"}, nil},
+		"tint4multirange": []any{
+			map[string]any{"lower": int64(1), "upper": int64(3), "bounds": "[)"},
+			map[string]any{"lower": int64(5), "upper": int64(7), "bounds": "[)"},
+		},
+		"ttstzrange": map[string]any{"lower": time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC), "upper": nil, "bounds": "[)"},
+		"tdaterange": map[string]any{"empty": true},
+		"tpoint":     []any{1.0, 2.0},
+		"tbox":       []any{[]any{3.0, 4.0}, []any{1.0, 2.0}},
+		"tpolygon":   []any{[]any{0.0, 0.0}, []any{0.0, 1.0}, []any{1.0, 0.0}},
+		"tmoney":     json.Number("12.34"),
+	}
+	for k, v := range want {
+		if got, want := rows[0][k], v; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got [%v:%T] want [%v:%T]", k, got, got, want, want)
+		}
+	}
+
+	set, err := FetchRowSet(ctx, testConnect, "cache.special", "fieldbags", pkvs)
+	check(t, err)
+	for _, k := range []string{"tint4range", "tint4ranges", "tint4multirange"} {
+		if got, want := set.RowMap(0)[k], want[k]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got [%v:%T] want [%v:%T]", k, got, got, want, want)
+		}
+	}
+}
+
+func TestFetchEnumAndDomain(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id := uuid.New()
+	_, err := testConnect.Exec(ctx, `insert into fieldbags (id,tmood,tposint) values ($1,'happy',7)`, id)
+	check(t, err)
+
+	columns, err := FetchColumns(ctx, testConnect, "fieldbags")
+	check(t, err)
+	for _, each := range columns {
+		switch each.Name {
+		case "tmood":
+			if got, want := each.EnumLabels, []string{"sad", "ok", "happy"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+			}
+			if got, want := each.UdtName, "mood"; got != want {
+				t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+			}
+		case "tposint":
+			if got, want := each.DomainName, "posint"; got != want {
+				t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+			}
+			if got, want := each.UdtName, "int4"; got != want {
+				t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+			}
+		}
+	}
+
+	rows, err := FetchRecords(ctx, testConnect, "cache.enum", "fieldbags", NewPrimaryKeyAndValues("id", id))
+	check(t, err)
+	if got, want := rows[0]["tmood"], "happy"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows[0]["tposint"], int64(7); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertThenFetchComposite(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id := uuid.New()
+	_, err := testConnect.Exec(ctx, `insert into fieldbags (id,taddress,taddresses) values ($1,ROW('Main St, "A"',12,NULL),ARRAY[ROW('Main St, "A"',12,NULL)::address,NULL])`, id)
+	check(t, err)
+
+	pkvs := NewPrimaryKeyAndValues("id", id)
+	rows, err := FetchRecords(ctx, testConnect, "cache.composite", "fieldbags", pkvs)
+	check(t, err)
+	want := Record{"street": `Main St, "A"`, "number": int64(12), "city": nil}
+	if got := rows[0]["taddress"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	set, err := FetchRowSet(ctx, testConnect, "cache.composite", "fieldbags", pkvs)
+	check(t, err)
+	if got, want := set.RowMap(0)["taddress"], map[string]any(want); !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows[0]["taddresses"], []any{want, nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := set.RowMap(0)["taddresses"], []any{map[string]any(want), nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	query, err := FetchQuery(ctx, testConnect, `select row(1, 'a') as pair`)
+	check(t, err)
+	if got, want := query.RowMap(0)["pair"], map[string]any{"f1": int64(1), "f2": "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFetchColumnDetails(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	columns, err := FetchColumns(context.Background(), testConnect, "fieldbags")
+	check(t, err)
+	named := map[string]*pb.ColumnSchema{}
+	for _, each := range columns {
+		named[each.Name] = each
+	}
+	if got, want := named["id"].OrdinalPosition, int32(1); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	varchar := named["tvarchar"]
+	if got, want := varchar.CharacterMaximumLength, int32(20); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := varchar.ColumnDefault, "'none'::character varying"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := varchar.Comment, "short text"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	amount := named["tamount"]
+	if got, want := []int32{amount.NumericPrecision, amount.NumericScale}, []int32{10, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	serial := named["tserial"]
+	if !serial.IsIdentity || serial.IdentityGeneration != "ALWAYS" || !serial.IsReadOnly() {
+		t.Errorf("identity expected, got %v", serial)
+	}
+	double := named["tdouble"]
+	if !double.IsGenerated || double.GenerationExpression != "(tinteger * 2)" || !double.IsReadOnly() {
+		t.Errorf("generated expected, got %v", double)
+	}
+	if varchar.IsReadOnly() {
+		t.Error("not read-only expected")
+	}
+}
+
+func TestFetchRelatedRows(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	_, err := testConnect.Exec(ctx, `
+	insert into customers (id, name) values (1, 'acme') on conflict do nothing;
+	insert into orders (id, customer_id) values (10, 1) on conflict do nothing;
+	insert into lines (order_id, nr, product) values (10, 1, 'bolt'), (10, 2, 'nut') on conflict do nothing;`)
+	check(t, err)
+
+	outgoing, incoming, err := FetchForeignKeys(ctx, testConnect, "orders")
+	check(t, err)
+	if got, want := len(outgoing), 1; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := outgoing[0].OnDelete, "CASCADE"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(incoming), 1; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := incoming[0].Name, "lines_order_fk"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	order := Record{"id": int64(10), "customer_id": int64(1)}
+	customers, err := FetchReferenced(ctx, testConnect, "cache.orders", "orders", order, "orders_customer_fk")
+	check(t, err)
+	if got, want := customers[0]["name"], "acme"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	lines, err := FetchReferencing(ctx, testConnect, "cache.orders", "orders", order, "lines_order_fk", FilterOrderBy("nr", false))
+	check(t, err)
+	if got, want := len(lines), 2; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := lines[1]["product"], "nut"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	// a customer refers to other customers, and can be referred to by them
+	_, err = testConnect.Exec(ctx, `
+	insert into customers (id, name) values (40, 'hooli') on conflict do nothing;
+	insert into customers (id, name, referrer_id) values (41, 'umbrella', 40) on conflict do nothing;`)
+	check(t, err)
+	referred, err := FetchReferencing(ctx, testConnect, "public.customers", "customers", Record{"id": int64(40), "referrer_id": nil}, "customers_referrer_id_fkey")
+	check(t, err)
+	if got, want := referred[0]["id"], int64(41); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	referrers, err := FetchReferenced(ctx, testConnect, "public.customers", "customers", referred[0], "customers_referrer_id_fkey")
+	check(t, err)
+	if got, want := referrers[0]["id"], int64(40); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestExtractSubsetRows(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	_, err := testConnect.Exec(ctx, `
+	insert into customers (id, name) values (2, 'globex') on conflict do nothing;
+	insert into customers (id, name, referrer_id) values (3, 'initech', 2) on conflict do nothing;
+	insert into orders (id, customer_id) values (20, 3) on conflict do nothing;
+	insert into lines (order_id, nr, product) values (20, 1, 'gear') on conflict do nothing;`)
+	check(t, err)
+
+	subset, err := ExtractSubset(ctx, testConnect, "cache.orders", "orders", NewPrimaryKeyAndValues("id", int64(20)), SubsetDepth(1))
+	check(t, err)
+	// the referrer of the customer is extracted too
+	if got, want := len(subset["public.customers"].Rows), 2; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(subset["public.lines"].Rows), 1; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestLoadBundleRows(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	_, err := testConnect.Exec(ctx, `delete from customers where id in (30, 31)`)
+	check(t, err)
+	row := func(values ...any) *pb.Row {
+		columns := []*pb.ColumnValue{}
+		for _, each := range values {
+			columns = append(columns, columnValueOf(each))
+		}
+		return &pb.Row{Columns: columns}
+	}
+	// the referrer is inserted after the customer that references it
+	bundle := map[string]*pb.RowSet{
+		"customers": {
+			ColumnSchemas: []*pb.ColumnSchema{{Name: "id"}, {Name: "name"}, {Name: "referrer_id"}},
+			Rows:          []*pb.Row{row(int64(31), "umbrella", int64(30)), row(int64(30), "hooli", nil)},
+		},
+	}
+	counts, err := LoadBundle(ctx, testConnect, bundle)
+	check(t, err)
+	if got, want := counts["public.customers"], 2; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	// the customers refer to each other
+	_, err = testConnect.Exec(ctx, `delete from customers where id in (32, 33)`)
+	check(t, err)
+	bundle = map[string]*pb.RowSet{
+		"customers": {
+			ColumnSchemas: []*pb.ColumnSchema{{Name: "id"}, {Name: "name"}, {Name: "referrer_id"}},
+			Rows:          []*pb.Row{row(int64(32), "initech", int64(33)), row(int64(33), "globex", int64(32))},
+		},
+	}
+	_, err = LoadBundle(ctx, testConnect, bundle)
+	check(t, err)
+	rows, err := FetchRecords(ctx, testConnect, "public.customers", "customers", NewPrimaryKeyAndValues("id", 32))
+	check(t, err)
+	if got, want := rows[0]["referrer_id"], int64(33); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestLoadBundleRoundTrip(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id, copyID := uuid.New(), uuid.New()
+	insertFullFieldbag(t, id)
+	bundle, err := ExtractSubset(ctx, testConnect, "public.fieldbags", "fieldbags", NewPrimaryKeyAndValues("id", id))
+	check(t, err)
+	set := bundle["public.fieldbags"]
+	set.Rows[0].Columns[0] = columnValueOf(copyID.String())
+	_, err = LoadBundle(ctx, testConnect, bundle)
+	check(t, err)
+	copied, err := FetchRowSet(ctx, testConnect, "public.fieldbags", "fieldbags", NewPrimaryKeyAndValues("id", copyID))
+	check(t, err)
+	if got, want := copied.JSONString(), set.JSONString(); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFetchTableSchema(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ts, err := FetchTableSchema(context.Background(), testConnect, "lines")
+	check(t, err)
+	if got, want := len(ts.ColumnSchemas), 3; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	// the unique constraint has an index too
+	if got, want := len(ts.Indexes), 3; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	pk := ts.Indexes[0]
+	if !pk.IsPrimary || !pk.IsUnique || pk.Method != "btree" || !reflect.DeepEqual(pk.Columns, []string{"order_id", "nr"}) {
+		t.Errorf("primary key index expected, got %v", pk)
+	}
+	partial := ts.Indexes[1]
+	if got, want := partial.Predicate, "product IS NOT NULL"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(ts.UniqueConstraints), 1; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := ts.CheckConstraints[0].Expression, "nr > 0"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if !ts.IsIndexed("order_id") {
+		t.Error("order_id indexed expected")
+	}
+}
+
+func TestFetchSchemaMetadataTables(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	check(t, WarmCache(ctx, testConnect, "public"))
+	schemas, err := FetchSchemaMetadata(ctx, testConnect, "public")
+	check(t, err)
+	lines := schemas["public.lines"]
+	if lines == nil {
+		t.Fatal("lines expected")
+	}
+	single, err := FetchTableSchema(ctx, testConnect, "lines")
+	check(t, err)
+	if got, want := len(lines.ColumnSchemas), len(single.ColumnSchemas); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(lines.Indexes), len(single.Indexes); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if _, ok := metaCache.Get("public.fieldbags"); !ok {
+		t.Error("cached fieldbags expected")
+	}
+}
+
+// insertFullFieldbag inserts a row of fieldbags with a value for each column that is not generated.
+func insertFullFieldbag(t *testing.T, id uuid.UUID) {
+	_, err := testConnect.Exec(context.Background(), `insert into fieldbags (id,tdate,ttimestamp,ttimestamptz,ttime,tinterval,tjsonb,tjson,ttext,
+		tnumeric,tdecimal,tdoubleprecision,tfloat,tinteger,tsmallint,tbigint,tboolean,tintarray,ttextarray,tuuidarray,tnumericarray,tmatrix,
+		tbytea,tinet,tcidr,tmacaddr,tint4range,ttstzrange,tdaterange,tpoint,tbox,tpolygon,tmoney,tmood,tposint,taddress,tvarchar,tamount)
+		values ($1,'2024-02-29','2024-02-29 13:14:15','2024-02-29 13:14:15.123456+01','13:14:15.5','1 year 2 months -3 days 04:05:06.5',
+		'{"a":[1,2]}','{"b":true}','text "quoted"',
+		'12345678901234567890.123','-0.5','1.5e300','NaN',42,-7,9007199254740993,true,'{1,NULL,3}','{"a","b c","d\"e"}',ARRAY[$1::uuid],'{12.50}','{{1,2},{3,4}}',
+		'\x00ff','192.168.0.1','10.0.0.0/8','08:00:2b:01:02:03','[1,10)','[2024-01-02 10:00:00+01,)','[2024-01-01,2024-02-01)',
+		'(1,2)','((1,2),(3,4))','((0,0),(0,1),(1,0))','12.34','happy',7,ROW('Main St, "A"',12,NULL),'short','3.14')`, id)
+	check(t, err)
+}
+
+func TestInsertRowSetRoundTrip(t *testing.T) {
+	if testConnect == nil {
+		t.Skip("no connection")
+	}
+	ctx := context.Background()
+	id, copyID := uuid.New(), uuid.New()
+	insertFullFieldbag(t, id)
+	// the identity value is copied and the generated value computed again
+	set, err := FetchRowSet(ctx, testConnect, "cache.roundtrip", "fieldbags", NewPrimaryKeyAndValues("id", id))
+	check(t, err)
+	set.Rows[0].Columns[0] = columnValueOf(copyID.String())
+	_, err = InsertRowSet(ctx, testConnect, "cache.roundtrip", set)
+	check(t, err)
+	copied, err := FetchRowSet(ctx, testConnect, "cache.roundtrip", "fieldbags", NewPrimaryKeyAndValues("id", copyID))
+	check(t, err)
+	if got, want := copied.JSONString(), set.JSONString(); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
 }
@@ -0,0 +1,102 @@
+package anyrow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+func TestInsertRecords(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7)}}
+	keys, err := InsertRecords(ctx, conn, "testpkkey", "pktest", []Record{{"str": "shoe", "unknown": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `INSERT INTO public.pktest ("str") VALUES ($1) RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.args, []any{"shoe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := keys[0]["id"], int64(7); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertRecordsStrict(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7)}}
+	_, err := InsertRecords(ctx, conn, "testpkkey", "pktest", []Record{{"str": "shoe", "unknown": 1}}, WriteStrict())
+	if err == nil {
+		t.Fatal("error expected")
+	}
+	if conn.sql != "" {
+		t.Errorf("unexpected query: %q", conn.sql)
+	}
+}
+
+func TestInsertRecordsDefaultValues(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7)}}
+	_, err := InsertRecords(ctx, conn, "testpkkey", "pktest", []Record{{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `INSERT INTO public.pktest DEFAULT VALUES RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertRowSet(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7)}}
+	set := &pb.RowSet{
+		SchemaName:    "public",
+		TableName:     "pktest",
+		ColumnSchemas: []*pb.ColumnSchema{{Name: "id"}, {Name: "str"}},
+		Rows: []*pb.Row{{Columns: []*pb.ColumnValue{
+			{JsonValue: &pb.ColumnValue_NumberIntegerValue{NumberIntegerValue: 7}},
+			{JsonValue: &pb.ColumnValue_StringValue{StringValue: "shoe"}},
+		}}},
+	}
+	keys, err := InsertRowSet(ctx, conn, "testpkkey", set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `INSERT INTO public.pktest ("id","str") VALUES ($1,$2) RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(keys), 1; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertRecordsEnum(t *testing.T) {
+	set := testMetaSet()
+	set.ColumnSchemas = append(set.ColumnSchemas, &pb.ColumnSchema{
+		Name:       "mood",
+		TypeName:   "USER-DEFINED",
+		UdtName:    "mood",
+		EnumLabels: []string{"sad", "ok", "happy"},
+		IsNullable: true,
+	})
+	metaCache.Set("testenumkey", set, 0)
+	ctx := context.Background()
+	// no primary key to return
+	conn := &mockQuerier{values: []any{}}
+	if _, err := InsertRecords(ctx, conn, "testenumkey", "test", []Record{{"mood": "grumpy"}}); err == nil {
+		t.Fatal("error expected")
+	}
+	if conn.sql != "" {
+		t.Errorf("unexpected query: %q", conn.sql)
+	}
+	if _, err := InsertRecords(ctx, conn, "testenumkey", "test", []Record{{"mood": "happy"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.args, []any{"happy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
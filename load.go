@@ -0,0 +1,244 @@
+package anyrow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/emicklei/anyrow/pb"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// Transactor can begin a transaction, such as a *pgx.Conn or a *pgxpool.Pool.
+type Transactor interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// LoadBundle inserts the rows of multiple tables inside one transaction and returns the number of inserted rows by table.
+// The bundle maps table names, optionally qualified by their schema, to their rows, as returned by ExtractSubset.
+// Tables are inserted in the order of their foreign keys such that rows are inserted after the rows they reference.
+// Rows of a table that references itself are inserted after the rows of the same table they reference.
+// If rows still reference rows that are inserted later, because tables or rows reference each other,
+// their foreign key columns are inserted as NULL and updated after all rows are inserted;
+// this requires those columns to be nullable and their table to have a primary key.
+// Values of generated columns are not inserted; values of identity columns are, overriding those of the database.
+// The metadata of the tables is cached by their qualified name.
+func LoadBundle(ctx context.Context, conn Transactor, bundle map[string]*pb.RowSet, options ...writeOption) (map[string]int, error) {
+	cfg := newWriteConfig(options)
+	cfg.overriding = true
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	sets := map[string]*pb.RowSet{}
+	for name, set := range bundle {
+		sets[qualifiedName(splitTableName(name))] = set
+	}
+	order, foreignKeys, err := loadOrder(ctx, tx, sets)
+	if err != nil {
+		return nil, err
+	}
+	state := &loadState{sets: sets, inserted: map[*pb.Row]bool{}, parents: map[*pb.ForeignKey]map[string]*pb.Row{}}
+	updates := []loadUpdate{}
+	counts := map[string]int{}
+	for _, name := range order {
+		metaSet, err := cachedMetadata(ctx, tx, name, name)
+		if err != nil {
+			return nil, err
+		}
+		set := state.parentsFirst(name, foreignKeys[name])
+		records := rowSetRecords(metaSet, set)
+		for i, row := range set.Rows {
+			late := Record{}
+			for _, each := range foreignKeys[name] {
+				if parent := state.referencedRow(set, row, each); parent != nil && parent != row && !state.inserted[parent] {
+					for _, column := range each.Columns {
+						late[column] = records[i][column]
+						records[i][column] = nil
+					}
+				}
+			}
+			if len(late) > 0 {
+				update, err := newLoadUpdate(metaSet, name, records[i], late)
+				if err != nil {
+					return nil, err
+				}
+				updates = append(updates, update)
+			}
+			state.inserted[row] = true
+		}
+		slog.Debug("LoadBundle", "table", name, "rows", len(records))
+		if _, err := insertRecords(ctx, tx, metaSet, records, cfg); err != nil {
+			return nil, fmt.Errorf("LoadBundle failed: %w, table:%s", err, name)
+		}
+		counts[name] = len(records)
+	}
+	for _, each := range updates {
+		if _, err := UpdateRecord(ctx, tx, each.name, each.name, each.pkv, each.record); err != nil {
+			return nil, fmt.Errorf("LoadBundle failed: %w, table:%s", err, each.name)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// loadUpdate sets the foreign key columns of an inserted row that references rows inserted after it.
+type loadUpdate struct {
+	name   string
+	pkv    PrimaryKeysAndValues
+	record Record
+}
+
+func newLoadUpdate(metaSet *pb.RowSet, name string, record, late Record) (loadUpdate, error) {
+	pairs := []PrimaryKeyAndValue{}
+	for _, each := range metaSet.ColumnSchemas {
+		if each.IsPrimarykey {
+			pairs = append(pairs, PrimaryKeyAndValue{Column: each.Name, Value: record[each.Name]})
+		}
+	}
+	if len(pairs) == 0 {
+		return loadUpdate{}, fmt.Errorf("LoadBundle failed: table %s has rows that reference rows inserted later but has no primary key", name)
+	}
+	return loadUpdate{name: name, pkv: NewPrimaryKeysAndValues(pairs), record: late}, nil
+}
+
+// loadState keeps track of the rows of a bundle that are inserted.
+type loadState struct {
+	sets     map[string]*pb.RowSet
+	inserted map[*pb.Row]bool
+	// rows of the referenced table by their key values, per foreign key
+	parents map[*pb.ForeignKey]map[string]*pb.Row
+}
+
+// referencedRow returns the row of the bundle that the row references by the foreign key, or nil if not part of the bundle.
+func (s *loadState) referencedRow(set *pb.RowSet, row *pb.Row, fk *pb.ForeignKey) *pb.Row {
+	key, ok := rowColumnsKey(set, row, fk.Columns)
+	if !ok {
+		return nil
+	}
+	parents, ok := s.parents[fk]
+	if !ok {
+		parents = map[string]*pb.Row{}
+		parentSet := s.sets[qualifiedName(fk.ReferencedSchemaName, fk.ReferencedTableName)]
+		for _, each := range parentSet.GetRows() {
+			if key, ok := rowColumnsKey(parentSet, each, fk.ReferencedColumns); ok {
+				parents[key] = each
+			}
+		}
+		s.parents[fk] = parents
+	}
+	return parents[key]
+}
+
+// parentsFirst returns the set of the table with its rows sorted such that rows come after the rows of the same table they reference.
+// Rows that reference each other keep their order.
+func (s *loadState) parentsFirst(name string, foreignKeys []*pb.ForeignKey) *pb.RowSet {
+	set := s.sets[name]
+	self := []*pb.ForeignKey{}
+	for _, each := range foreignKeys {
+		if qualifiedName(each.ReferencedSchemaName, each.ReferencedTableName) == name {
+			self = append(self, each)
+		}
+	}
+	if len(self) == 0 {
+		return set
+	}
+	placed := map[*pb.Row]bool{}
+	rows := []*pb.Row{}
+	for len(rows) < len(set.Rows) {
+		progress := false
+		for _, row := range set.Rows {
+			if placed[row] {
+				continue
+			}
+			if slices.ContainsFunc(self, func(fk *pb.ForeignKey) bool {
+				parent := s.referencedRow(set, row, fk)
+				return parent != nil && parent != row && !placed[parent]
+			}) {
+				continue
+			}
+			rows = append(rows, row)
+			placed[row] = true
+			progress = true
+		}
+		if !progress {
+			// the remaining rows reference each other
+			for _, row := range set.Rows {
+				if !placed[row] {
+					rows = append(rows, row)
+					placed[row] = true
+				}
+			}
+		}
+	}
+	return &pb.RowSet{SchemaName: set.SchemaName, TableName: set.TableName, ColumnSchemas: set.ColumnSchemas, Rows: rows}
+}
+
+// rowColumnsKey returns the key of the values of the columns of a row; it returns false if a column is absent or NULL.
+func rowColumnsKey(set *pb.RowSet, row *pb.Row, columns []string) (string, bool) {
+	cells := make([]*pb.ColumnValue, len(columns))
+	for i, each := range columns {
+		index := slices.IndexFunc(set.ColumnSchemas, func(s *pb.ColumnSchema) bool { return s.Name == each })
+		if index < 0 || index >= len(row.Columns) || row.Columns[index].Value() == nil {
+			return "", false
+		}
+		cells[i] = row.Columns[index]
+	}
+	return columnsKey(cells), true
+}
+
+// loadOrder returns the qualified table names sorted such that each table comes after the tables it references,
+// and the foreign keys of each table that reference tables of the bundle, including itself.
+// Tables that reference each other are sorted by name.
+func loadOrder(ctx context.Context, conn Querier, sets map[string]*pb.RowSet) (order []string, foreignKeys map[string][]*pb.ForeignKey, err error) {
+	names := []string{}
+	for name := range sets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	// referenced tables of the bundle by qualified table name
+	parents := map[string][]string{}
+	foreignKeys = map[string][]*pb.ForeignKey{}
+	for _, name := range names {
+		outgoing, _, err := getForeignKeys(ctx, conn, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, each := range outgoing {
+			parent := qualifiedName(each.ReferencedSchemaName, each.ReferencedTableName)
+			if _, ok := sets[parent]; !ok {
+				continue
+			}
+			foreignKeys[name] = append(foreignKeys[name], each)
+			if parent != name {
+				parents[name] = append(parents[name], parent)
+			}
+		}
+	}
+	for len(order) < len(names) {
+		placed := false
+		for _, name := range names {
+			if slices.Contains(order, name) {
+				continue
+			}
+			if slices.ContainsFunc(parents[name], func(p string) bool { return !slices.Contains(order, p) }) {
+				continue
+			}
+			order = append(order, name)
+			placed = true
+		}
+		if !placed {
+			// the remaining tables reference each other
+			for _, name := range names {
+				if !slices.Contains(order, name) {
+					order = append(order, name)
+				}
+			}
+		}
+	}
+	return order, foreignKeys, nil
+}
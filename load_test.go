@@ -0,0 +1,163 @@
+package anyrow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/anyrow/pb"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// mockTx is a transaction that runs its queries on a mockQuerier.
+type mockTx struct {
+	pgx.Tx
+	*mockQuerier
+	committed bool
+	// arguments of each query
+	argsList [][]any
+}
+
+func (m *mockTx) Begin(ctx context.Context) (pgx.Tx, error) { return m, nil }
+func (m *mockTx) Commit(ctx context.Context) error          { m.committed = true; return nil }
+func (m *mockTx) Rollback(ctx context.Context) error        { return nil }
+func (m *mockTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	m.argsList = append(m.argsList, args)
+	return m.mockQuerier.Query(ctx, sql, args...)
+}
+
+func orderBundle() map[string]*pb.RowSet {
+	return map[string]*pb.RowSet{
+		"orders": {Rows: []*pb.Row{{Columns: []*pb.ColumnValue{columnValueOf(int64(1)), columnValueOf(int64(7))}}},
+			ColumnSchemas: []*pb.ColumnSchema{{Name: "id"}, {Name: "customer_id"}}},
+		"public.customers": {Rows: []*pb.Row{{Columns: []*pb.ColumnValue{columnValueOf(int64(7)), columnValueOf("acme")}}},
+			ColumnSchemas: []*pb.ColumnSchema{{Name: "id"}, {Name: "name"}}},
+	}
+}
+
+func TestLoadBundle(t *testing.T) {
+	setupOrderKeys()
+	setupLineKeys()
+	conn := &mockTx{mockQuerier: &mockQuerier{results: [][][]any{
+		// foreign keys of customers
+		{orderCustomerKey(false, true)},
+		// foreign keys of orders
+		{orderCustomerKey(true, false)},
+		{{int64(7)}},
+		{{int64(1)}},
+	}}}
+	counts, err := LoadBundle(context.Background(), conn, orderBundle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sqls[2], `INSERT INTO public.customers ("id","name") VALUES ($1,$2) RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.sqls[3], `INSERT INTO public.orders ("id","customer_id") VALUES ($1,$2) RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := counts, map[string]int{"public.orders": 1, "public.customers": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if !conn.committed {
+		t.Error("commit expected")
+	}
+}
+
+// setupReferrerKeys caches the metadata of customers that can refer to other customers.
+func setupReferrerKeys() {
+	metaCache.Set("public.customers", &pb.RowSet{SchemaName: "public", TableName: "customers", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "name", TypeName: "text"},
+		{Name: "referrer_id", TypeName: "bigint", IsNullable: true},
+	}}, 0)
+}
+
+func referrerKey() []any {
+	return []any{"customers_referrer_id_fkey", "public", "customers", []string{"referrer_id"}, "public", "customers", []string{"id"}, "a", "a", true, true}
+}
+
+func referrerBundle(rows ...*pb.Row) map[string]*pb.RowSet {
+	return map[string]*pb.RowSet{"customers": {Rows: rows,
+		ColumnSchemas: []*pb.ColumnSchema{{Name: "id"}, {Name: "name"}, {Name: "referrer_id"}}}}
+}
+
+func referrerRow(id int64, name string, referrerID any) *pb.Row {
+	return &pb.Row{Columns: []*pb.ColumnValue{columnValueOf(id), columnValueOf(name), columnValueOf(referrerID)}}
+}
+
+func TestLoadBundleSelfReference(t *testing.T) {
+	setupReferrerKeys()
+	conn := &mockTx{mockQuerier: &mockQuerier{results: [][][]any{
+		{referrerKey()},
+		{{int64(30)}},
+		{{int64(31)}},
+	}}}
+	// the referrer is inserted before the customer that references it
+	bundle := referrerBundle(referrerRow(31, "umbrella", int64(30)), referrerRow(30, "hooli", nil))
+	if _, err := LoadBundle(context.Background(), conn, bundle); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(conn.sqls), 3; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.argsList[1], []any{int64(30), "hooli", nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.argsList[2], []any{int64(31), "umbrella", int64(30)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestLoadBundleReferenceCycle(t *testing.T) {
+	setupReferrerKeys()
+	conn := &mockTx{mockQuerier: &mockQuerier{results: [][][]any{
+		{referrerKey()},
+		{{int64(1)}},
+		{{int64(2)}},
+		{},
+	}}}
+	// the customers refer to each other
+	bundle := referrerBundle(referrerRow(1, "a", int64(2)), referrerRow(2, "b", int64(1)))
+	if _, err := LoadBundle(context.Background(), conn, bundle); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sqls[3], `UPDATE public.customers SET "referrer_id"=$2 WHERE (id=$1)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	// the first customer is inserted without its referrer, which is set after the second is inserted
+	if got, want := conn.argsList[1], []any{int64(1), "a", nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.argsList[3], []any{int64(1), int64(2)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestLoadOrderCycle(t *testing.T) {
+	conn := &mockQuerier{results: [][][]any{
+		{{"a_b_fk", "public", "a", []string{"b_id"}, "public", "b", []string{"id"}, "a", "a", true, false}},
+		{{"b_a_fk", "public", "b", []string{"a_id"}, "public", "a", []string{"id"}, "a", "a", true, false}},
+		{},
+	}}
+	order, foreignKeys, err := loadOrder(context.Background(), conn, map[string]*pb.RowSet{"public.a": {}, "public.b": {}, "public.c": {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := order, []string{"public.c", "public.a", "public.b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := foreignKeys["public.a"][0].Name, "a_b_fk"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertStatementOverriding(t *testing.T) {
+	set := &pb.RowSet{SchemaName: "public", TableName: "t", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", IsPrimarykey: true, IsIdentity: true, IdentityGeneration: "ALWAYS"},
+	}}
+	sql, _ := insertStatement(set, primaryKeySet(set), Record{"id": 1}, writeConfig{overriding: true})
+	if got, want := sql, `INSERT INTO public.t ("id") OVERRIDING SYSTEM VALUE VALUES ($1) RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
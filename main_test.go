@@ -16,6 +16,7 @@ var testConnect *pgx.Conn
 
 func TestMain(m *testing.M) {
 	setupTestKey()
+	setupTestPKKey()
 	connectionString := os.Getenv("ANYROW_CONN")
 	if len(connectionString) == 0 {
 		println("no database env set")
@@ -48,10 +49,19 @@ func ensureTables(conn *pgx.Conn) error {
 	}
 	_, err = conn.Exec(ctx, `
 	drop table IF EXISTS fieldbags;
+	drop type IF EXISTS mood;
+	create type mood as enum ('sad', 'ok', 'happy');
+	drop domain IF EXISTS posint;
+	drop type IF EXISTS address;
+	create type address as (street text, number integer, city text);
+	create domain posint as integer check (value > 0);
 	create table fieldbags (
 		id uuid,
 		tdate date,
 		ttimestamp timestamp without time zone,
+		ttimestamptz timestamp with time zone,
+		ttime time,
+		tinterval interval,
 		tjsonb jsonb,
 		tjson json,
 		ttext text,
@@ -62,8 +72,55 @@ func ensureTables(conn *pgx.Conn) error {
 		tinteger integer,
 		tsmallint smallint,
 		tbigint bigint,
-		tboolean boolean
-	);`)
+		tboolean boolean,
+		tintarray integer[],
+		ttextarray text[],
+		tuuidarray uuid[],
+		tnumericarray numeric[],
+		tmatrix integer[][],
+		tbytea bytea,
+		tinet inet,
+		tcidr cidr,
+		tmacaddr macaddr,
+		tint4range int4range,
+		tint4ranges int4range[],
+		tint4multirange int4multirange,
+		ttstzrange tstzrange,
+		tdaterange daterange,
+		tpoint point,
+		tbox box,
+		tpolygon polygon,
+		tmoney money,
+		tmood mood,
+		tposint posint,
+		taddress address,
+		taddresses address[],
+		tvarchar varchar(20) default 'none',
+		tamount numeric(10,2),
+		tserial integer generated always as identity,
+		tdouble integer generated always as (tinteger * 2) stored
+	);
+	comment on column fieldbags.tvarchar is 'short text';
+	drop table IF EXISTS lines;
+	drop table IF EXISTS orders;
+	drop table IF EXISTS customers;
+	create table customers (
+		id bigint primary key,
+		name text,
+		referrer_id bigint references customers (id)
+	);
+	create table orders (
+		id bigint primary key,
+		customer_id bigint constraint orders_customer_fk references customers (id) on delete cascade
+	);
+	create table lines (
+		order_id bigint constraint lines_order_fk references orders (id),
+		nr integer check (nr > 0),
+		product text,
+		primary key (order_id, nr),
+		constraint lines_product_key unique (order_id, product)
+	);
+	create index lines_product_idx on lines (lower(product)) where product is not null;`)
 	if err != nil {
 		tx.Rollback(ctx)
 		return err
@@ -72,6 +129,11 @@ func ensureTables(conn *pgx.Conn) error {
 }
 
 func setupTestKey() {
+	metaCache.Set("testkey", testMetaSet(), cache.DefaultExpiration)
+}
+
+// testMetaSet returns the metadata of the test table.
+func testMetaSet() *pb.RowSet {
 	set := new(pb.RowSet)
 	set.SchemaName = "public"
 	set.TableName = "test"
@@ -87,5 +149,22 @@ func setupTestKey() {
 		IsNullable:   true,
 		IsPrimarykey: false,
 	})
-	metaCache.Set("testkey", set, cache.DefaultExpiration)
+	return set
+}
+
+func setupTestPKKey() {
+	set := new(pb.RowSet)
+	set.SchemaName = "public"
+	set.TableName = "pktest"
+	set.ColumnSchemas = append(set.ColumnSchemas, &pb.ColumnSchema{
+		Name:         "id",
+		TypeName:     "bigint",
+		IsPrimarykey: true,
+	})
+	set.ColumnSchemas = append(set.ColumnSchemas, &pb.ColumnSchema{
+		Name:       "str",
+		TypeName:   "text",
+		IsNullable: true,
+	})
+	metaCache.Set("testpkkey", set, cache.DefaultExpiration)
 }
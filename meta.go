@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,54 +21,206 @@ func init() {
 	metaCache = cache.New(defaultExpiration, 10*time.Minute)
 }
 
-func getMetadata(ctx context.Context, conn Querier, tableName string) (*pb.RowSet, error) {
-	schema := "public"
-	if strings.Contains(tableName, ".") {
-		schema = tableName[:strings.Index(tableName, ".")]
-		tableName = tableName[strings.Index(tableName, ".")+1:]
+// cachedMetadata returns the metadata of a table from the cache or queries and caches it.
+// If the key is not cached, the metadata cached by the qualified table name, such as by WarmCache, is used.
+func cachedMetadata(ctx context.Context, conn Querier, metadataCacheKey, tableName string) (*pb.RowSet, error) {
+	set, ok := metaCache.Get(metadataCacheKey)
+	if ok {
+		return set.(*pb.RowSet), nil
+	}
+	if set, ok := metaCache.Get(qualifiedName(splitTableName(tableName))); ok {
+		return set.(*pb.RowSet), nil
+	}
+	mset, err := getMetadata(ctx, conn, tableName)
+	if err != nil {
+		return nil, err
 	}
+	metaCache.Set(metadataCacheKey, mset, defaultExpiration)
+	return mset, nil
+}
+
+// splitTableName returns the schema and the name of a table name that is optionally qualified by its schema.
+// The schema defaults to public.
+func splitTableName(tableName string) (string, string) {
+	if schema, name, ok := strings.Cut(tableName, "."); ok {
+		return schema, name
+	}
+	return "public", tableName
+}
+
+func getMetadata(ctx context.Context, conn Querier, tableName string) (*pb.RowSet, error) {
+	schema, tableName := splitTableName(tableName)
 	qualifiedTableName := fmt.Sprintf("%s.%s", schema, tableName)
+	sets, err := queryMetadata(ctx, conn, "cl.oid = CAST($1 as regclass)", qualifiedTableName)
+	if err != nil {
+		return nil, fmt.Errorf("getMetadata failed: %w, table:%s, schema:%s", err, tableName, schema)
+	}
+	if set, ok := sets[tableName]; ok {
+		return set, nil
+	}
+	// a table without columns
+	return &pb.RowSet{SchemaName: schema, TableName: tableName}, nil
+}
+
+// queryMetadata returns the metadata of the tables that match the condition by table name.
+// The condition is on the pg_namespace ns and pg_class cl of the tables and has one parameter.
+func queryMetadata(ctx context.Context, conn Querier, condition string, arg any) (map[string]*pb.RowSet, error) {
 	query := `
-SELECT column_name, data_type, is_nullable,
+SELECT isc.table_schema, isc.table_name,
+	isc.column_name, isc.data_type, isc.is_nullable,
 	EXISTS (
 		SELECT 1
 		FROM pg_constraint c
 		JOIN pg_attribute a ON a.attnum = ANY(c.conkey) AND a.attrelid = c.conrelid
 		WHERE c.contype = 'p'
-		  AND c.conrelid = CAST($1 as regclass)
+		  AND c.conrelid = cl.oid
 		  AND a.attname = isc.column_name
-	) AS isPrimary
+	) AS isPrimary,
+	isc.udt_name,
+	CASE WHEN t.typtype = 'd' THEN t.typbasetype ELSE t.oid END AS typeOID,
+	COALESCE(isc.domain_name, '') AS domainName,
+	ARRAY(
+		SELECT e.enumlabel
+		FROM pg_enum e
+		WHERE e.enumtypid = CASE WHEN t.typtype = 'd' THEN t.typbasetype ELSE t.oid END
+		ORDER BY e.enumsortorder
+	) AS enumLabels,
+	COALESCE(et.oid, 0) AS elementTypeOID,
+	CASE WHEN t.typtype = 'c' THEN t.oid WHEN et.typtype = 'c' THEN et.oid ELSE 0 END AS compositeOID,
+	isc.ordinal_position::int,
+	COALESCE(isc.column_default, '')::text,
+	COALESCE(isc.character_maximum_length, 0)::int,
+	COALESCE(isc.numeric_precision, 0)::int,
+	COALESCE(isc.numeric_scale, 0)::int,
+	isc.is_identity = 'YES',
+	COALESCE(isc.identity_generation, '')::text,
+	isc.is_generated = 'ALWAYS',
+	COALESCE(isc.generation_expression, '')::text,
+	COALESCE(col_description(pa.attrelid, pa.attnum), '')
 FROM information_schema.columns isc
-WHERE table_name = $2 AND table_schema = $3;
+JOIN pg_namespace ns ON ns.nspname = isc.table_schema
+JOIN pg_class cl ON cl.relnamespace = ns.oid AND cl.relname = isc.table_name
+JOIN pg_attribute pa ON pa.attrelid = cl.oid AND pa.attname = isc.column_name
+JOIN pg_type t ON t.oid = pa.atttypid
+LEFT JOIN pg_type et ON et.oid = t.typelem AND t.typcategory = 'A'
+WHERE ` + condition + `
+ORDER BY isc.table_name, isc.ordinal_position;
 `
-	rows, err := conn.Query(ctx, query, qualifiedTableName, tableName, schema)
+	rows, err := conn.Query(ctx, query, arg)
 	if err != nil {
-		return nil, fmt.Errorf("getMetadata failed: %w, table:%s, schema:%s", err, tableName, schema)
+		return nil, err
 	}
 	defer rows.Close()
 
-	set := new(pb.RowSet)
-	set.SchemaName = schema
-	set.TableName = tableName
+	sets := map[string]*pb.RowSet{}
+	// composite type OIDs of columns of a composite type or an array of a composite type
+	composites := map[*pb.ColumnSchema]uint32{}
 	for rows.Next() {
-		var columnName, dataType, isNullable string
-		var isPrimary bool
-		if err := rows.Scan(&columnName, &dataType, &isNullable, &isPrimary); err != nil {
+		var schema, tableName, isNullable string
+		var compositeOID uint32
+		column := new(pb.ColumnSchema)
+		if err := rows.Scan(&schema, &tableName,
+			&column.Name, &column.TypeName, &isNullable, &column.IsPrimarykey, &column.UdtName, &column.TypeOid, &column.DomainName, &column.EnumLabels,
+			&column.ElementTypeOid, &compositeOID,
+			&column.OrdinalPosition, &column.ColumnDefault, &column.CharacterMaximumLength, &column.NumericPrecision, &column.NumericScale,
+			&column.IsIdentity, &column.IdentityGeneration, &column.IsGenerated, &column.GenerationExpression, &column.Comment); err != nil {
 			var pgErr *pgconn.PgError
 			if errors.As(err, &pgErr) {
 				fmt.Println(pgErr.Message) // => syntax error at end of input
 				fmt.Println(pgErr.Code)    // => 42601
 			}
-			return nil, fmt.Errorf("getMetadata.scan failed: %w, table:%s, schema:%s", err, tableName, schema)
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		column.IsNullable = isNullable == "YES"
+		set, ok := sets[tableName]
+		if !ok {
+			set = &pb.RowSet{SchemaName: schema, TableName: tableName}
+			sets[tableName] = set
+		}
+		set.ColumnSchemas = append(set.ColumnSchemas, column)
+		if compositeOID != 0 {
+			composites[column] = compositeOID
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// the connection can only run the next query when the rows are closed
+	if err := setAttributeSchemas(ctx, conn, composites); err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// setAttributeSchemas sets the schemas of the attributes of the composite types of the columns, nested for attributes of composite types.
+// The attributes of all composite types involved are queried at once.
+func setAttributeSchemas(ctx context.Context, conn Querier, composites map[*pb.ColumnSchema]uint32) error {
+	query := `
+WITH RECURSIVE composite(oid) AS (
+	SELECT unnest($1::oid[])
+	UNION
+	SELECT CASE WHEN t.typtype = 'c' THEN t.oid ELSE et.oid END
+	FROM composite c
+	JOIN pg_type ct ON ct.oid = c.oid
+	JOIN pg_attribute a ON a.attrelid = ct.typrelid AND a.attnum > 0 AND NOT a.attisdropped
+	JOIN pg_type t ON t.oid = a.atttypid
+	LEFT JOIN pg_type et ON et.oid = t.typelem AND t.typcategory = 'A'
+	WHERE t.typtype = 'c' OR et.typtype = 'c'
+)
+SELECT c.oid, a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull,
+	t.typname,
+	CASE WHEN t.typtype = 'd' THEN t.typbasetype ELSE t.oid END AS typeOID,
+	COALESCE(et.oid, 0) AS elementTypeOID,
+	CASE WHEN t.typtype = 'c' THEN t.oid WHEN et.typtype = 'c' THEN et.oid ELSE 0 END AS compositeOID
+FROM composite c
+JOIN pg_type ct ON ct.oid = c.oid
+JOIN pg_attribute a ON a.attrelid = ct.typrelid
+JOIN pg_type t ON t.oid = a.atttypid
+LEFT JOIN pg_type et ON et.oid = t.typelem AND t.typcategory = 'A'
+WHERE a.attnum > 0 AND NOT a.attisdropped
+ORDER BY c.oid, a.attnum;
+`
+	if len(composites) == 0 {
+		return nil
+	}
+	typeOIDs := []uint32{}
+	for _, each := range composites {
+		if !slices.Contains(typeOIDs, each) {
+			typeOIDs = append(typeOIDs, each)
 		}
-		set.ColumnSchemas = append(set.ColumnSchemas, &pb.ColumnSchema{
-			Name:         columnName,
-			TypeName:     dataType,
-			IsNullable:   isNullable == "YES",
-			IsPrimarykey: isPrimary,
-		})
-	}
-	return set, nil
+	}
+	slices.Sort(typeOIDs)
+	rows, err := conn.Query(ctx, query, typeOIDs)
+	if err != nil {
+		return fmt.Errorf("setAttributeSchemas failed: %w, types:%v", err, typeOIDs)
+	}
+	defer rows.Close()
+	attributesByType := map[uint32][]*pb.ColumnSchema{}
+	nested := map[*pb.ColumnSchema]uint32{}
+	for rows.Next() {
+		var typeOID, compositeOID uint32
+		var isNullable bool
+		attribute := new(pb.ColumnSchema)
+		if err := rows.Scan(&typeOID, &attribute.Name, &attribute.TypeName, &isNullable, &attribute.UdtName, &attribute.TypeOid, &attribute.ElementTypeOid, &compositeOID); err != nil {
+			return fmt.Errorf("setAttributeSchemas.scan failed: %w, types:%v", err, typeOIDs)
+		}
+		attribute.IsNullable = isNullable
+		attributesByType[typeOID] = append(attributesByType[typeOID], attribute)
+		if compositeOID != 0 {
+			nested[attribute] = compositeOID
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("setAttributeSchemas failed: %w, types:%v", err, typeOIDs)
+	}
+	for attribute, typeOID := range nested {
+		attribute.AttributeSchemas = attributesByType[typeOID]
+	}
+	for column, typeOID := range composites {
+		column.AttributeSchemas = attributesByType[typeOID]
+	}
+	return nil
 }
 
 func getTableNames(ctx context.Context, conn Querier, schema string) ([]string, error) {
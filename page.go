@@ -0,0 +1,100 @@
+package anyrow
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/emicklei/anyrow/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Page holds the Records of one page and the token to fetch the next page.
+type Page struct {
+	Records []Record
+	// NextToken is empty if there are no more pages.
+	NextToken string
+}
+
+// FilterPage queries a table using a WHERE clause and returns one page of Records ordered by the primary key columns.
+// Pass an empty pageToken for the first page and the NextToken of a Page for the next one.
+// Pages use keyset pagination so the table must have a primary key. Unless option is given, the page size is 1000.
+func FilterPage(ctx context.Context, conn Querier, metadataCacheKey, tableName string, where string, pageToken string, options ...filterOption) (Page, error) {
+	page := Page{}
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return page, err
+	}
+	keys := primaryKeySet(set)
+	if len(keys.ColumnSchemas) == 0 {
+		return page, fmt.Errorf("table %s.%s has no primary key, required for pagination", set.SchemaName, set.TableName)
+	}
+	filter := newFetchFilter(where, defaultFilterLimit, options)
+	if filter.limit <= 0 {
+		return page, errors.New("limit parameter must be greater than zero")
+	}
+	if len(filter.orderBy) > 0 {
+		return page, errors.New("pages are ordered by the primary key, order columns are not supported")
+	}
+	set, err = filter.project(set)
+	if err != nil {
+		return page, err
+	}
+	for _, each := range keys.ColumnSchemas {
+		if columnSchemaNamed(set, each.Name) == nil {
+			return page, fmt.Errorf("primary key column %q must be selected for pagination", each.Name)
+		}
+		filter.orderBy = append(filter.orderBy, columnOrder{column: each.Name})
+	}
+	if pageToken != "" {
+		after, err := decodePageToken(pageToken)
+		if err != nil {
+			return page, err
+		}
+		if len(after) != len(filter.orderBy) {
+			return page, fmt.Errorf("page token does not match the primary key of table %s.%s", set.SchemaName, set.TableName)
+		}
+		filter.after = after
+	}
+	pageSize := filter.limit
+	// fetch one more to detect a next page
+	filter.limit++
+	collector := &objectCollector{
+		set: set,
+	}
+	if err := fetchValues(ctx, conn, set, filter, collector); err != nil {
+		return page, err
+	}
+	page.Records = collector.list
+	if len(page.Records) > pageSize {
+		page.Records = page.Records[:pageSize]
+		last := page.Records[pageSize-1]
+		token := new(pb.PageToken)
+		for _, each := range filter.orderBy {
+			token.KeyValues = append(token.KeyValues, columnValueOf(last[each.column]))
+		}
+		data, err := proto.Marshal(token)
+		if err != nil {
+			return page, err
+		}
+		page.NextToken = base64.RawURLEncoding.EncodeToString(data)
+	}
+	return page, nil
+}
+
+func decodePageToken(pageToken string) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page token: %w", err)
+	}
+	token := new(pb.PageToken)
+	if err := proto.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("invalid page token: %w", err)
+	}
+	values := make([]any, len(token.KeyValues))
+	for i, each := range token.KeyValues {
+		values[i] = each.Value()
+	}
+	return values, nil
+}
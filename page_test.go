@@ -0,0 +1,57 @@
+package anyrow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFilterPage(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7), "shoe"}, rowCount: 3}
+	page, err := FilterPage(ctx, conn, "testpkkey", "pktest", "str <> ''", "", FilterLimit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `SELECT "id","str" FROM public.pktest WHERE str <> '' ORDER BY "id" LIMIT 3`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(page.Records), 2; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if page.NextToken == "" {
+		t.Fatal("next token expected")
+	}
+	conn.rowCount = 1
+	page, err = FilterPage(ctx, conn, "testpkkey", "pktest", "str <> ''", page.NextToken, FilterLimit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `SELECT "id","str" FROM public.pktest WHERE (str <> '') AND ("id") > ($1) ORDER BY "id" LIMIT 3`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.args, []any{int64(7)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := page.NextToken, ""; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestFilterPageWithoutPrimaryKey(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	_, err := FilterPage(ctx, conn, "testkey", "test", "", "")
+	if err == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestFilterPageInvalidToken(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	_, err := FilterPage(ctx, conn, "testpkkey", "pktest", "", "not a token!")
+	if err == nil {
+		t.Fatal("error expected")
+	}
+}
@@ -0,0 +1,239 @@
+package anyrow
+
+import (
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// parameterValue returns a column value as a query parameter for a column of a table.
+// Scalar values are returned as Go values that pgx encodes for the data type of the column.
+// Arrays, ranges, composites and geometric values are returned in the text format of Postgres,
+// which the database parses according to the data type of the column.
+func parameterValue(schema *pb.ColumnSchema, cell *pb.ColumnValue) any {
+	switch v := cell.GetJsonValue().(type) {
+	case nil, *pb.ColumnValue_NullValue:
+		return nil
+	case *pb.ColumnValue_NumberIntegerValue:
+		return v.NumberIntegerValue
+	case *pb.ColumnValue_NumberFloatValue:
+		return v.NumberFloatValue
+	case *pb.ColumnValue_NumberDoubleValue:
+		return v.NumberDoubleValue
+	case *pb.ColumnValue_BoolValue:
+		return v.BoolValue
+	case *pb.ColumnValue_BytesValue:
+		return v.BytesValue
+	case *pb.ColumnValue_TimestampValue:
+		return v.TimestampValue.AsTime()
+	case *pb.ColumnValue_TimeValue:
+		return pgtype.Time{Microseconds: v.TimeValue.GetMicroseconds(), Valid: true}
+	case *pb.ColumnValue_IntervalValue:
+		return pgtype.Interval{
+			Microseconds: v.IntervalValue.GetMicroseconds(),
+			Days:         v.IntervalValue.GetDays(),
+			Months:       v.IntervalValue.GetMonths(),
+			Valid:        true,
+		}
+	}
+	text, _ := literalText(schema.TypeOid, cell)
+	return text
+}
+
+// literalText returns a column value in the text format of Postgres for a data type.
+// It returns false if the value is NULL.
+func literalText(typeOID uint32, cell *pb.ColumnValue) (string, bool) {
+	switch v := cell.GetJsonValue().(type) {
+	case *pb.ColumnValue_StringValue:
+		return v.StringValue, true
+	case *pb.ColumnValue_ObjectValue:
+		return v.ObjectValue, true
+	case *pb.ColumnValue_DecimalValue:
+		return v.DecimalValue, true
+	case *pb.ColumnValue_NumberIntegerValue:
+		return strconv.FormatInt(v.NumberIntegerValue, 10), true
+	case *pb.ColumnValue_NumberFloatValue:
+		return floatText(float64(v.NumberFloatValue), 32), true
+	case *pb.ColumnValue_NumberDoubleValue:
+		return floatText(v.NumberDoubleValue, 64), true
+	case *pb.ColumnValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue), true
+	case *pb.ColumnValue_BytesValue:
+		return `\x` + hex.EncodeToString(v.BytesValue), true
+	case *pb.ColumnValue_TimestampValue:
+		return v.TimestampValue.AsTime().Format(time.RFC3339Nano), true
+	case *pb.ColumnValue_DateValue:
+		return v.DateValue.Format(), true
+	case *pb.ColumnValue_TimeValue:
+		return v.TimeValue.Format(), true
+	case *pb.ColumnValue_IntervalValue:
+		return v.IntervalValue.Format(), true
+	case *pb.ColumnValue_ArrayValue:
+		if isGeometricOID(typeOID) {
+			return geometricText(typeOID, v.ArrayValue.Values()), true
+		}
+		if rangeOID, ok := multirangeRangeOID(typeOID); ok {
+			return multirangeText(rangeOID, v.ArrayValue), true
+		}
+		elementOID, _ := arrayElementOID(typeOID)
+		return arrayText(elementOID, v.ArrayValue), true
+	case *pb.ColumnValue_RangeValue:
+		return rangeText(typeOID, v.RangeValue), true
+	case *pb.ColumnValue_CompositeValue:
+		return compositeText(v.CompositeValue), true
+	}
+	return "", false
+}
+
+// floatText returns a float as text; NaN and infinite values are written as Postgres does.
+func floatText(f float64, bitSize int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+// quoteLiteral returns the text double-quoted as an element of an array, range or composite literal.
+func quoteLiteral(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+// arrayText returns an array literal such as {1,NULL,"a b"}, nested for multidimensional arrays.
+func arrayText(elementOID uint32, array *pb.Array) string {
+	delimiter := ","
+	if elementOID == pgtype.BoxOID {
+		// a box has commas in its text
+		delimiter = ";"
+	}
+	b := new(strings.Builder)
+	b.WriteRune('{')
+	for i, each := range array.GetElements() {
+		if i > 0 {
+			b.WriteString(delimiter)
+		}
+		if nested, ok := each.GetJsonValue().(*pb.ColumnValue_ArrayValue); ok && !isGeometricOID(elementOID) {
+			b.WriteString(arrayText(elementOID, nested.ArrayValue))
+			continue
+		}
+		text, ok := literalText(elementOID, each)
+		if !ok {
+			b.WriteString("NULL")
+			continue
+		}
+		b.WriteString(quoteLiteral(text))
+	}
+	b.WriteRune('}')
+	return b.String()
+}
+
+// rangeText returns a range literal such as [1,5) or empty.
+func rangeText(typeOID uint32, value *pb.Range) string {
+	if value.GetEmpty() {
+		return "empty"
+	}
+	elementOID, _ := rangeElementOID(typeOID)
+	bounds := value.GetBounds()
+	if len(bounds) != 2 {
+		bounds = "[)"
+	}
+	b := new(strings.Builder)
+	b.WriteByte(bounds[0])
+	if text, ok := literalText(elementOID, value.GetLower()); ok {
+		b.WriteString(quoteLiteral(text))
+	}
+	b.WriteRune(',')
+	if text, ok := literalText(elementOID, value.GetUpper()); ok {
+		b.WriteString(quoteLiteral(text))
+	}
+	b.WriteByte(bounds[1])
+	return b.String()
+}
+
+// multirangeText returns a multirange literal such as {[1,3),[5,7)}; its ranges are not quoted.
+func multirangeText(rangeOID uint32, ranges *pb.Array) string {
+	list := make([]string, len(ranges.GetElements()))
+	for i, each := range ranges.GetElements() {
+		list[i] = rangeText(rangeOID, each.GetRangeValue())
+	}
+	return "{" + strings.Join(list, ",") + "}"
+}
+
+// compositeText returns a composite literal such as ("Main St",12,); a NULL attribute has no text.
+func compositeText(row *pb.RowWithSchema) string {
+	b := new(strings.Builder)
+	b.WriteRune('(')
+	for i, each := range row.GetColumns() {
+		if i > 0 {
+			b.WriteRune(',')
+		}
+		var typeOID uint32
+		if i < len(row.GetSchemas()) {
+			typeOID = row.GetSchemas()[i].GetTypeOid()
+		}
+		if text, ok := literalText(typeOID, each); ok {
+			b.WriteString(quoteLiteral(text))
+		}
+	}
+	b.WriteRune(')')
+	return b.String()
+}
+
+func isGeometricOID(oid uint32) bool {
+	switch oid {
+	case pgtype.PointOID, pgtype.LsegOID, pgtype.BoxOID, pgtype.PathOID, pgtype.PolygonOID, pgtype.LineOID, pgtype.CircleOID:
+		return true
+	}
+	return false
+}
+
+// geometricText returns the text of a geometric value from its coordinates, see geometricCoordinates.
+// A path is written as an open path because the coordinates do not tell whether it was closed.
+func geometricText(typeOID uint32, coordinates []any) string {
+	switch typeOID {
+	case pgtype.PointOID:
+		return pointText(coordinates)
+	case pgtype.LsegOID, pgtype.PathOID:
+		return "[" + pointsText(coordinates) + "]"
+	case pgtype.BoxOID:
+		return pointsText(coordinates)
+	case pgtype.PolygonOID:
+		return "(" + pointsText(coordinates) + ")"
+	case pgtype.LineOID:
+		return "{" + numbersText(coordinates) + "}"
+	case pgtype.CircleOID:
+		if len(coordinates) == 3 {
+			return "<" + pointText(coordinates[:2]) + "," + numbersText(coordinates[2:]) + ">"
+		}
+	}
+	return numbersText(coordinates)
+}
+
+func pointText(coordinates any) string {
+	list, _ := coordinates.([]any)
+	return "(" + numbersText(list) + ")"
+}
+
+func pointsText(points []any) string {
+	list := make([]string, len(points))
+	for i, each := range points {
+		list[i] = pointText(each)
+	}
+	return strings.Join(list, ",")
+}
+
+func numbersText(numbers []any) string {
+	list := make([]string, len(numbers))
+	for i, each := range numbers {
+		f, _ := each.(float64)
+		list[i] = floatText(f, 64)
+	}
+	return strings.Join(list, ",")
+}
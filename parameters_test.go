@@ -0,0 +1,104 @@
+package anyrow
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParameterValueScalars(t *testing.T) {
+	for _, each := range []struct {
+		typeOID uint32
+		cell    *pb.ColumnValue
+		want    any
+	}{
+		{pgtype.Int8OID, columnValueOf(int64(7)), int64(7)},
+		{pgtype.Int8OID, columnValueOf(nil), nil},
+		{pgtype.NumericOID, columnValueOf(json.Number("-123.4500")), "-123.4500"},
+		{pgtype.TimestamptzOID, columnValueOf(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{pgtype.DateOID, &pb.ColumnValue{JsonValue: &pb.ColumnValue_DateValue{DateValue: &pb.Date{Year: 2024, Month: 2, Day: 29}}}, "2024-02-29"},
+		{pgtype.TimeOID, columnValueOf(pgtype.Time{Microseconds: 3_600_000_000, Valid: true}), pgtype.Time{Microseconds: 3_600_000_000, Valid: true}},
+		{pgtype.IntervalOID, columnValueOf(pgtype.Interval{Days: 1, Months: 2, Valid: true}), pgtype.Interval{Days: 1, Months: 2, Valid: true}},
+		{pgtype.JSONBOID, &pb.ColumnValue{JsonValue: &pb.ColumnValue_ObjectValue{ObjectValue: `{"a":1}`}}, `{"a":1}`},
+	} {
+		if got, want := parameterValue(&pb.ColumnSchema{TypeOid: each.typeOID}, each.cell), each.want; !reflect.DeepEqual(got, want) {
+			t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+		}
+	}
+}
+
+func TestParameterValueLiterals(t *testing.T) {
+	address := &pb.ColumnValue{JsonValue: &pb.ColumnValue_CompositeValue{CompositeValue: rowWithSchemaOf(
+		[]*pb.ColumnSchema{{Name: "street", TypeOid: pgtype.TextOID}, {Name: "number", TypeOid: pgtype.Int4OID}, {Name: "city", TypeOid: pgtype.TextOID}},
+		Record{"street": `Main St, "A"`, "number": int64(12), "city": nil})}}
+	for _, each := range []struct {
+		typeOID uint32
+		cell    *pb.ColumnValue
+		want    string
+	}{
+		{pgtype.Int4ArrayOID, columnValueOf([]any{int64(1), nil, int64(3)}), `{"1",NULL,"3"}`},
+		{pgtype.TextArrayOID, columnValueOf([]any{`a "b"`, `c\d`}), `{"a \"b\"","c\\d"}`},
+		{pgtype.Int4ArrayOID, columnValueOf([]any{[]any{int64(1), int64(2)}, []any{int64(3), int64(4)}}), `{{"1","2"},{"3","4"}}`},
+		{pgtype.Float8ArrayOID, columnValueOf([]any{math.Inf(-1), 0.5}), `{"-Infinity","0.5"}`},
+		{pgtype.ByteaArrayOID, columnValueOf([]any{[]byte{1, 255}}), `{"\\x01ff"}`},
+		{pgtype.Int4rangeOID, &pb.ColumnValue{JsonValue: &pb.ColumnValue_RangeValue{RangeValue: &pb.Range{Lower: columnValueOf(int64(1)), Upper: columnValueOf(nil), Bounds: "[)"}}}, `["1",)`},
+		{pgtype.Int4rangeOID, &pb.ColumnValue{JsonValue: &pb.ColumnValue_RangeValue{RangeValue: &pb.Range{Empty: true}}}, `empty`},
+		{0, address, `("Main St, \"A\"","12",)`},
+		{pgtype.PointOID, columnValueOf([]any{1.5, -2.0}), `(1.5,-2)`},
+		{pgtype.BoxOID, columnValueOf([]any{[]any{3.0, 4.0}, []any{1.0, 2.0}}), `(3,4),(1,2)`},
+		{pgtype.PolygonOID, columnValueOf([]any{[]any{0.0, 0.0}, []any{1.0, 0.0}, []any{1.0, 1.0}}), `((0,0),(1,0),(1,1))`},
+		{pgtype.BoxArrayOID, columnValueOf([]any{[]any{[]any{3.0, 4.0}, []any{1.0, 2.0}}, nil}), `{"(3,4),(1,2)";NULL}`},
+	} {
+		if got, want := parameterValue(&pb.ColumnSchema{TypeOid: each.typeOID}, each.cell), each.want; got != want {
+			t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+		}
+	}
+}
+
+func TestInsertRowSetParameters(t *testing.T) {
+	set := testMetaSet()
+	set.ColumnSchemas = append(set.ColumnSchemas, &pb.ColumnSchema{Name: "tags", TypeName: "ARRAY", TypeOid: pgtype.TextArrayOID})
+	metaCache.Set("testarraykey", set, 0)
+	conn := &mockQuerier{values: []any{}}
+	rows := &pb.RowSet{
+		TableName:     "test",
+		ColumnSchemas: []*pb.ColumnSchema{{Name: "num"}, {Name: "tags"}},
+		Rows:          []*pb.Row{{Columns: []*pb.ColumnValue{columnValueOf(int64(42)), columnValueOf([]any{"a", nil})}}},
+	}
+	if _, err := InsertRowSet(context.Background(), conn, "testarraykey", rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.args, []any{int64(42), `{"a",NULL}`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestInsertRowSetReadOnlyColumns(t *testing.T) {
+	metaCache.Set("testreadonlykey", &pb.RowSet{SchemaName: "public", TableName: "counters", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeOid: pgtype.Int8OID, IsPrimarykey: true, IsIdentity: true, IdentityGeneration: "ALWAYS"},
+		{Name: "n", TypeOid: pgtype.Int8OID},
+		{Name: "twice", TypeOid: pgtype.Int8OID, IsGenerated: true},
+	}}, 0)
+	conn := &mockQuerier{values: []any{int64(1)}}
+	rows := &pb.RowSet{
+		SchemaName:    "public",
+		TableName:     "counters",
+		ColumnSchemas: []*pb.ColumnSchema{{Name: "id"}, {Name: "n"}, {Name: "twice"}},
+		Rows:          []*pb.Row{{Columns: []*pb.ColumnValue{columnValueOf(int64(1)), columnValueOf(int64(2)), columnValueOf(int64(4))}}},
+	}
+	if _, err := InsertRowSet(context.Background(), conn, "testreadonlykey", rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `INSERT INTO public.counters ("id","n") OVERRIDING SYSTEM VALUE VALUES ($1,$2) RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.args, []any{int64(1), int64(2)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
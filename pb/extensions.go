@@ -1,8 +1,13 @@
 package pb
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // RowMap returns a map representation of the row at the specified index in the
@@ -12,27 +17,51 @@ import (
 func (x *RowSet) RowMap(rowIndex int) map[string]interface{} {
 	m := make(map[string]interface{}, len(x.Rows[rowIndex].Columns))
 	for i, each := range x.Rows[rowIndex].Columns {
-		key := x.ColumnSchemas[i].Name
-		switch each.GetJsonValue().(type) {
-		case *ColumnValue_StringValue:
-			m[key] = each.GetStringValue()
-		case *ColumnValue_NumberFloatValue:
-			m[key] = each.GetNumberFloatValue()
-		case *ColumnValue_NumberIntegerValue:
-			m[key] = each.GetNumberIntegerValue()
-		case *ColumnValue_ObjectValue:
-			m[key] = each.GetObjectValue()
-		case *ColumnValue_ArrayValue:
-			m[key] = each.GetArrayValue()
-		case *ColumnValue_BoolValue:
-			m[key] = each.GetBoolValue()
-		default:
-			m[key] = nil
-		}
+		m[x.ColumnSchemas[i].Name] = each.Value()
 	}
 	return m
 }
 
+// Value returns the Go value of the column value or nil if NULL or not set.
+func (x *ColumnValue) Value() any {
+	switch x.GetJsonValue().(type) {
+	case *ColumnValue_StringValue:
+		return x.GetStringValue()
+	case *ColumnValue_NumberFloatValue:
+		return x.GetNumberFloatValue()
+	case *ColumnValue_NumberIntegerValue:
+		return x.GetNumberIntegerValue()
+	case *ColumnValue_ObjectValue:
+		return x.GetObjectValue()
+	case *ColumnValue_ArrayValue:
+		return x.GetArrayValue().Values()
+	case *ColumnValue_BoolValue:
+		return x.GetBoolValue()
+	case *ColumnValue_NumberDoubleValue:
+		return x.GetNumberDoubleValue()
+	case *ColumnValue_DecimalValue:
+		return json.Number(x.GetDecimalValue())
+	case *ColumnValue_TimestampValue:
+		return x.GetTimestampValue().AsTime()
+	case *ColumnValue_DateValue:
+		return x.GetDateValue().Time()
+	case *ColumnValue_TimeValue:
+		return x.GetTimeValue()
+	case *ColumnValue_IntervalValue:
+		return x.GetIntervalValue()
+	case *ColumnValue_NullValue:
+		return nil
+	case *ColumnValue_BytesValue:
+		return x.GetBytesValue()
+	case *ColumnValue_RangeValue:
+		return x.GetRangeValue().Map()
+	case *ColumnValue_CompositeValue:
+		return x.GetCompositeValue().Map()
+	default:
+		return nil
+	}
+}
+
 // JSONString returns a JSON-encoded string representation of the RowSet.
 func (x *RowSet) JSONString() string {
 	buf := new(strings.Builder)
@@ -74,28 +103,209 @@ func (x *RowSet) encodeRowOn(rowIndex int, enc *json.Encoder, buf *strings.Build
 		// assume no escaping needed for name
 		buf.WriteString(x.ColumnSchemas[c].Name)
 		buf.WriteString(`":`)
-		switch other.GetJsonValue().(type) {
-		case *ColumnValue_StringValue:
-			enc.Encode(other.GetStringValue())
-		case *ColumnValue_NumberFloatValue:
-			enc.Encode(other.GetNumberFloatValue())
-		case *ColumnValue_NumberIntegerValue:
-			enc.Encode(other.GetNumberIntegerValue())
-		case *ColumnValue_ObjectValue:
-			buf.WriteString(other.GetObjectValue())
-			buf.WriteRune('\n')
-		case *ColumnValue_ArrayValue:
-			buf.WriteString(other.GetArrayValue())
-			buf.WriteRune('\n')
-		case *ColumnValue_BoolValue:
-			if other.GetBoolValue() {
-				buf.WriteString("true\n")
-			} else {
-				buf.WriteString("false\n")
+		encodeValueOn(other, enc, buf)
+	}
+	buf.WriteRune('}')
+}
+
+// encodeValueOn writes the JSON encoding of a column value followed by a newline.
+func encodeValueOn(value *ColumnValue, enc *json.Encoder, buf *strings.Builder) {
+	switch value.GetJsonValue().(type) {
+	case *ColumnValue_StringValue:
+		enc.Encode(value.GetStringValue())
+	case *ColumnValue_NumberFloatValue:
+		f := value.GetNumberFloatValue()
+		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+			// not supported by JSON
+			enc.Encode(strconv.FormatFloat(float64(f), 'g', -1, 32))
+		} else {
+			enc.Encode(f)
+		}
+	case *ColumnValue_NumberIntegerValue:
+		enc.Encode(value.GetNumberIntegerValue())
+	case *ColumnValue_ObjectValue:
+		buf.WriteString(value.GetObjectValue())
+		buf.WriteRune('\n')
+	case *ColumnValue_ArrayValue:
+		buf.WriteRune('[')
+		for i, each := range value.GetArrayValue().GetElements() {
+			if i > 0 {
+				buf.WriteRune(',')
+			}
+			encodeValueOn(each, enc, buf)
+		}
+		buf.WriteString("]\n")
+	case *ColumnValue_BoolValue:
+		if value.GetBoolValue() {
+			buf.WriteString("true\n")
+		} else {
+			buf.WriteString("false\n")
+		}
+	case *ColumnValue_NumberDoubleValue:
+		f := value.GetNumberDoubleValue()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			// not supported by JSON
+			enc.Encode(strconv.FormatFloat(f, 'g', -1, 64))
+		} else {
+			enc.Encode(f)
+		}
+	case *ColumnValue_DecimalValue:
+		// unquoted to keep all digits
+		buf.WriteString(value.GetDecimalValue())
+		buf.WriteRune('\n')
+	case *ColumnValue_TimestampValue:
+		enc.Encode(value.GetTimestampValue().AsTime().Format(time.RFC3339Nano))
+	case *ColumnValue_DateValue:
+		enc.Encode(value.GetDateValue().Format())
+	case *ColumnValue_TimeValue:
+		enc.Encode(value.GetTimeValue().Format())
+	case *ColumnValue_IntervalValue:
+		enc.Encode(value.GetIntervalValue().Format())
+	case *ColumnValue_NullValue:
+		buf.WriteString("null\n")
+	case *ColumnValue_BytesValue:
+		// base64 with padding
+		enc.Encode(value.GetBytesValue())
+	case *ColumnValue_RangeValue:
+		r := value.GetRangeValue()
+		if r.GetEmpty() {
+			buf.WriteString("{\"empty\":true}\n")
+			return
+		}
+		buf.WriteString(`{"lower":`)
+		encodeValueOn(r.GetLower(), enc, buf)
+		buf.WriteString(`,"upper":`)
+		encodeValueOn(r.GetUpper(), enc, buf)
+		buf.WriteString(`,"bounds":`)
+		enc.Encode(r.GetBounds())
+		buf.WriteString("}\n")
+	case *ColumnValue_CompositeValue:
+		row := value.GetCompositeValue()
+		buf.WriteRune('{')
+		for i, each := range row.GetColumns() {
+			if i > 0 {
+				buf.WriteRune(',')
 			}
-		default:
-			buf.WriteString("null\n")
+			buf.WriteRune('"')
+			// assume no escaping needed for name
+			buf.WriteString(row.GetSchemas()[i].GetName())
+			buf.WriteString(`":`)
+			encodeValueOn(each, enc, buf)
 		}
+		buf.WriteString("}\n")
+	default:
+		buf.WriteString("null\n")
 	}
-	buf.WriteRune('}')
+}
+
+// Values returns the Go values of the elements, nested for multidimensional arrays.
+func (x *Array) Values() []any {
+	values := make([]any, len(x.GetElements()))
+	for i, each := range x.GetElements() {
+		values[i] = each.Value()
+	}
+	return values
+}
+
+// Map returns the range as a map with keys lower, upper and bounds, or with key empty if the range is empty.
+func (x *Range) Map() map[string]any {
+	if x.GetEmpty() {
+		return map[string]any{"empty": true}
+	}
+	return map[string]any{
+		"lower":  x.GetLower().Value(),
+		"upper":  x.GetUpper().Value(),
+		"bounds": x.GetBounds(),
+	}
+}
+
+// IsReadOnly returns true if the value of the column is always computed by the database,
+// as for a generated column or an identity column that is GENERATED ALWAYS.
+func (x *ColumnSchema) IsReadOnly() bool {
+	return x.GetIsGenerated() || (x.GetIsIdentity() && x.GetIdentityGeneration() == "ALWAYS")
+}
+
+// Map returns the values of the columns by their name.
+func (x *RowWithSchema) Map() map[string]any {
+	m := make(map[string]any, len(x.GetColumns()))
+	for i, each := range x.GetColumns() {
+		m[x.GetSchemas()[i].GetName()] = each.Value()
+	}
+	return m
+}
+
+// Time returns the date as a time.Time at midnight UTC.
+func (x *Date) Time() time.Time {
+	return time.Date(int(x.GetYear()), time.Month(x.GetMonth()), int(x.GetDay()), 0, 0, 0, 0, time.UTC)
+}
+
+// Format returns the date as YYYY-MM-DD.
+func (x *Date) Format() string {
+	return fmt.Sprintf("%04d-%02d-%02d", x.GetYear(), x.GetMonth(), x.GetDay())
+}
+
+// Format returns the time as HH:MM:SS with a fraction of microseconds if not zero.
+func (x *TimeOfDay) Format() string {
+	us := x.GetMicroseconds()
+	s := fmt.Sprintf("%02d:%02d:%02d", us/3600_000_000, us/60_000_000%60, us/1_000_000%60)
+	if frac := us % 1_000_000; frac != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+	}
+	return s
+}
+
+// Value implements driver.Valuer such that a time of day can be passed as a query parameter.
+func (x *TimeOfDay) Value() (driver.Value, error) {
+	return x.Format(), nil
+}
+
+// Format returns the interval in ISO 8601 duration format, e.g. P1Y2M3DT4H5M6.5S.
+// Like Postgres, each component carries its own sign.
+func (x *Interval) Format() string {
+	b := new(strings.Builder)
+	b.WriteRune('P')
+	if years := x.GetMonths() / 12; years != 0 {
+		fmt.Fprintf(b, "%dY", years)
+	}
+	if months := x.GetMonths() % 12; months != 0 {
+		fmt.Fprintf(b, "%dM", months)
+	}
+	if x.GetDays() != 0 {
+		fmt.Fprintf(b, "%dD", x.GetDays())
+	}
+	us := x.GetMicroseconds()
+	if us == 0 {
+		if b.Len() == 1 {
+			return "PT0S"
+		}
+		return b.String()
+	}
+	b.WriteRune('T')
+	if hours := us / 3600_000_000; hours != 0 {
+		fmt.Fprintf(b, "%dH", hours)
+	}
+	if minutes := us / 60_000_000 % 60; minutes != 0 {
+		fmt.Fprintf(b, "%dM", minutes)
+	}
+	if seconds := us % 60_000_000; seconds != 0 {
+		b.WriteString(strconv.FormatFloat(float64(seconds)/1e6, 'f', -1, 64))
+		b.WriteRune('S')
+	}
+	return b.String()
+}
+
+// IsIndexed returns true if the column is the first key column of a btree index that is not partial.
+// Such an index can be used to sort the rows of the table by that column.
+func (x *TableSchema) IsIndexed(column string) bool {
+	for _, each := range x.GetIndexes() {
+		if each.GetMethod() == "btree" && each.GetPredicate() == "" && len(each.GetColumns()) > 0 && each.GetColumns()[0] == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements driver.Valuer such that an interval can be passed as a query parameter.
+func (x *Interval) Value() (driver.Value, error) {
+	return x.Format(), nil
 }
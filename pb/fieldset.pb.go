@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.26.0
+// 	protoc-gen-go v1.36.6
 // 	protoc        v3.17.3
 // source: fieldset.proto
 
@@ -11,8 +11,11 @@ package pb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -23,23 +26,20 @@ const (
 )
 
 type RowSet struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TableName     string                 `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	ColumnSchemas []*ColumnSchema        `protobuf:"bytes,2,rep,name=column_schemas,json=columnSchemas,proto3" json:"column_schemas,omitempty"`
+	Rows          []*Row                 `protobuf:"bytes,3,rep,name=rows,proto3" json:"rows,omitempty"`
+	SchemaName    string                 `protobuf:"bytes,4,opt,name=schema_name,json=schemaName,proto3" json:"schema_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	TableName     string          `protobuf:"bytes,1,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
-	ColumnSchemas []*ColumnSchema `protobuf:"bytes,2,rep,name=column_schemas,json=columnSchemas,proto3" json:"column_schemas,omitempty"`
-	Rows          []*Row          `protobuf:"bytes,3,rep,name=rows,proto3" json:"rows,omitempty"`
-	SchemaName    string          `protobuf:"bytes,4,opt,name=schema_name,json=schemaName,proto3" json:"schema_name,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RowSet) Reset() {
 	*x = RowSet{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_fieldset_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_fieldset_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *RowSet) String() string {
@@ -50,7 +50,7 @@ func (*RowSet) ProtoMessage() {}
 
 func (x *RowSet) ProtoReflect() protoreflect.Message {
 	mi := &file_fieldset_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -94,21 +94,18 @@ func (x *RowSet) GetSchemaName() string {
 }
 
 type RowWithSchema struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schemas       []*ColumnSchema        `protobuf:"bytes,1,rep,name=schemas,proto3" json:"schemas,omitempty"`
+	Columns       []*ColumnValue         `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Schemas []*ColumnSchema `protobuf:"bytes,1,rep,name=schemas,proto3" json:"schemas,omitempty"`
-	Columns []*ColumnValue  `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RowWithSchema) Reset() {
 	*x = RowWithSchema{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_fieldset_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_fieldset_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *RowWithSchema) String() string {
@@ -119,7 +116,7 @@ func (*RowWithSchema) ProtoMessage() {}
 
 func (x *RowWithSchema) ProtoReflect() protoreflect.Message {
 	mi := &file_fieldset_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -149,23 +146,49 @@ func (x *RowWithSchema) GetColumns() []*ColumnValue {
 }
 
 type ColumnSchema struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	TypeName     string `protobuf:"bytes,2,opt,name=type_name,json=typeName,proto3" json:"type_name,omitempty"`
-	IsNullable   bool   `protobuf:"varint,3,opt,name=is_nullable,json=isNullable,proto3" json:"is_nullable,omitempty"`
-	IsPrimarykey bool   `protobuf:"varint,4,opt,name=is_primarykey,json=isPrimarykey,proto3" json:"is_primarykey,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TypeName     string                 `protobuf:"bytes,2,opt,name=type_name,json=typeName,proto3" json:"type_name,omitempty"`
+	IsNullable   bool                   `protobuf:"varint,3,opt,name=is_nullable,json=isNullable,proto3" json:"is_nullable,omitempty"`
+	IsPrimarykey bool                   `protobuf:"varint,4,opt,name=is_primarykey,json=isPrimarykey,proto3" json:"is_primarykey,omitempty"`
+	// OID of the data type, of the base type for a domain
+	TypeOid uint32 `protobuf:"varint,5,opt,name=type_oid,json=typeOid,proto3" json:"type_oid,omitempty"`
+	// name of the data type or, for a domain, of its base type
+	UdtName string `protobuf:"bytes,6,opt,name=udt_name,json=udtName,proto3" json:"udt_name,omitempty"`
+	// name of the domain if the data type is a domain
+	DomainName string `protobuf:"bytes,7,opt,name=domain_name,json=domainName,proto3" json:"domain_name,omitempty"`
+	// allowed values in sort order if the data type is an enum or a domain over an enum
+	EnumLabels []string `protobuf:"bytes,8,rep,name=enum_labels,json=enumLabels,proto3" json:"enum_labels,omitempty"`
+	// attributes in order if the data type, or the element type of an array, is a composite type
+	AttributeSchemas []*ColumnSchema `protobuf:"bytes,9,rep,name=attribute_schemas,json=attributeSchemas,proto3" json:"attribute_schemas,omitempty"`
+	// position in the table, starting at 1
+	OrdinalPosition int32 `protobuf:"varint,10,opt,name=ordinal_position,json=ordinalPosition,proto3" json:"ordinal_position,omitempty"`
+	// expression of the default value, empty if none
+	ColumnDefault string `protobuf:"bytes,11,opt,name=column_default,json=columnDefault,proto3" json:"column_default,omitempty"`
+	// maximum length of a character type, 0 if not declared
+	CharacterMaximumLength int32 `protobuf:"varint,12,opt,name=character_maximum_length,json=characterMaximumLength,proto3" json:"character_maximum_length,omitempty"`
+	// precision and scale of a numeric type, 0 if not applicable
+	NumericPrecision int32 `protobuf:"varint,13,opt,name=numeric_precision,json=numericPrecision,proto3" json:"numeric_precision,omitempty"`
+	NumericScale     int32 `protobuf:"varint,14,opt,name=numeric_scale,json=numericScale,proto3" json:"numeric_scale,omitempty"`
+	IsIdentity       bool  `protobuf:"varint,15,opt,name=is_identity,json=isIdentity,proto3" json:"is_identity,omitempty"`
+	// ALWAYS or BY DEFAULT for an identity column
+	IdentityGeneration string `protobuf:"bytes,16,opt,name=identity_generation,json=identityGeneration,proto3" json:"identity_generation,omitempty"`
+	IsGenerated        bool   `protobuf:"varint,17,opt,name=is_generated,json=isGenerated,proto3" json:"is_generated,omitempty"`
+	// expression of a generated column
+	GenerationExpression string `protobuf:"bytes,18,opt,name=generation_expression,json=generationExpression,proto3" json:"generation_expression,omitempty"`
+	// the comment on the column, empty if none
+	Comment string `protobuf:"bytes,19,opt,name=comment,proto3" json:"comment,omitempty"`
+	// OID of the element type if the data type is an array, 0 otherwise
+	ElementTypeOid uint32 `protobuf:"varint,20,opt,name=element_type_oid,json=elementTypeOid,proto3" json:"element_type_oid,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ColumnSchema) Reset() {
 	*x = ColumnSchema{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_fieldset_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_fieldset_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ColumnSchema) String() string {
@@ -176,7 +199,7 @@ func (*ColumnSchema) ProtoMessage() {}
 
 func (x *ColumnSchema) ProtoReflect() protoreflect.Message {
 	mi := &file_fieldset_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -219,21 +242,130 @@ func (x *ColumnSchema) GetIsPrimarykey() bool {
 	return false
 }
 
+func (x *ColumnSchema) GetTypeOid() uint32 {
+	if x != nil {
+		return x.TypeOid
+	}
+	return 0
+}
+
+func (x *ColumnSchema) GetUdtName() string {
+	if x != nil {
+		return x.UdtName
+	}
+	return ""
+}
+
+func (x *ColumnSchema) GetDomainName() string {
+	if x != nil {
+		return x.DomainName
+	}
+	return ""
+}
+
+func (x *ColumnSchema) GetEnumLabels() []string {
+	if x != nil {
+		return x.EnumLabels
+	}
+	return nil
+}
+
+func (x *ColumnSchema) GetAttributeSchemas() []*ColumnSchema {
+	if x != nil {
+		return x.AttributeSchemas
+	}
+	return nil
+}
+
+func (x *ColumnSchema) GetOrdinalPosition() int32 {
+	if x != nil {
+		return x.OrdinalPosition
+	}
+	return 0
+}
+
+func (x *ColumnSchema) GetColumnDefault() string {
+	if x != nil {
+		return x.ColumnDefault
+	}
+	return ""
+}
+
+func (x *ColumnSchema) GetCharacterMaximumLength() int32 {
+	if x != nil {
+		return x.CharacterMaximumLength
+	}
+	return 0
+}
+
+func (x *ColumnSchema) GetNumericPrecision() int32 {
+	if x != nil {
+		return x.NumericPrecision
+	}
+	return 0
+}
+
+func (x *ColumnSchema) GetNumericScale() int32 {
+	if x != nil {
+		return x.NumericScale
+	}
+	return 0
+}
+
+func (x *ColumnSchema) GetIsIdentity() bool {
+	if x != nil {
+		return x.IsIdentity
+	}
+	return false
+}
+
+func (x *ColumnSchema) GetIdentityGeneration() string {
+	if x != nil {
+		return x.IdentityGeneration
+	}
+	return ""
+}
+
+func (x *ColumnSchema) GetIsGenerated() bool {
+	if x != nil {
+		return x.IsGenerated
+	}
+	return false
+}
+
+func (x *ColumnSchema) GetGenerationExpression() string {
+	if x != nil {
+		return x.GenerationExpression
+	}
+	return ""
+}
+
+func (x *ColumnSchema) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *ColumnSchema) GetElementTypeOid() uint32 {
+	if x != nil {
+		return x.ElementTypeOid
+	}
+	return 0
+}
+
 type Row struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Columns       []*ColumnValue         `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Columns []*ColumnValue `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Row) Reset() {
 	*x = Row{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_fieldset_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_fieldset_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Row) String() string {
@@ -244,7 +376,7 @@ func (*Row) ProtoMessage() {}
 
 func (x *Row) ProtoReflect() protoreflect.Message {
 	mi := &file_fieldset_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -267,29 +399,37 @@ func (x *Row) GetColumns() []*ColumnValue {
 }
 
 type ColumnValue struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
+	state protoimpl.MessageState `protogen:"open.v1"`
 	// https://www.w3schools.com/js/js_json_datatypes.asp
 	//
-	// Types that are assignable to JsonValue:
+	// Types that are valid to be assigned to JsonValue:
+	//
 	//	*ColumnValue_StringValue
 	//	*ColumnValue_NumberFloatValue
 	//	*ColumnValue_NumberIntegerValue
 	//	*ColumnValue_ObjectValue
-	//	*ColumnValue_ArrayValue
 	//	*ColumnValue_BoolValue
-	JsonValue isColumnValue_JsonValue `protobuf_oneof:"json_value"`
+	//	*ColumnValue_NumberDoubleValue
+	//	*ColumnValue_DecimalValue
+	//	*ColumnValue_TimestampValue
+	//	*ColumnValue_DateValue
+	//	*ColumnValue_TimeValue
+	//	*ColumnValue_IntervalValue
+	//	*ColumnValue_NullValue
+	//	*ColumnValue_BytesValue
+	//	*ColumnValue_RangeValue
+	//	*ColumnValue_CompositeValue
+	//	*ColumnValue_ArrayValue
+	JsonValue     isColumnValue_JsonValue `protobuf_oneof:"json_value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ColumnValue) Reset() {
 	*x = ColumnValue{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_fieldset_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_fieldset_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *ColumnValue) String() string {
@@ -300,7 +440,7 @@ func (*ColumnValue) ProtoMessage() {}
 
 func (x *ColumnValue) ProtoReflect() protoreflect.Message {
 	mi := &file_fieldset_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -315,53 +455,155 @@ func (*ColumnValue) Descriptor() ([]byte, []int) {
 	return file_fieldset_proto_rawDescGZIP(), []int{4}
 }
 
-func (m *ColumnValue) GetJsonValue() isColumnValue_JsonValue {
-	if m != nil {
-		return m.JsonValue
+func (x *ColumnValue) GetJsonValue() isColumnValue_JsonValue {
+	if x != nil {
+		return x.JsonValue
 	}
 	return nil
 }
 
 func (x *ColumnValue) GetStringValue() string {
-	if x, ok := x.GetJsonValue().(*ColumnValue_StringValue); ok {
-		return x.StringValue
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_StringValue); ok {
+			return x.StringValue
+		}
 	}
 	return ""
 }
 
 func (x *ColumnValue) GetNumberFloatValue() float32 {
-	if x, ok := x.GetJsonValue().(*ColumnValue_NumberFloatValue); ok {
-		return x.NumberFloatValue
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_NumberFloatValue); ok {
+			return x.NumberFloatValue
+		}
 	}
 	return 0
 }
 
 func (x *ColumnValue) GetNumberIntegerValue() int64 {
-	if x, ok := x.GetJsonValue().(*ColumnValue_NumberIntegerValue); ok {
-		return x.NumberIntegerValue
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_NumberIntegerValue); ok {
+			return x.NumberIntegerValue
+		}
 	}
 	return 0
 }
 
 func (x *ColumnValue) GetObjectValue() string {
-	if x, ok := x.GetJsonValue().(*ColumnValue_ObjectValue); ok {
-		return x.ObjectValue
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_ObjectValue); ok {
+			return x.ObjectValue
+		}
 	}
 	return ""
 }
 
-func (x *ColumnValue) GetArrayValue() string {
-	if x, ok := x.GetJsonValue().(*ColumnValue_ArrayValue); ok {
-		return x.ArrayValue
+func (x *ColumnValue) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+func (x *ColumnValue) GetNumberDoubleValue() float64 {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_NumberDoubleValue); ok {
+			return x.NumberDoubleValue
+		}
+	}
+	return 0
+}
+
+func (x *ColumnValue) GetDecimalValue() string {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_DecimalValue); ok {
+			return x.DecimalValue
+		}
 	}
 	return ""
 }
 
-func (x *ColumnValue) GetBoolValue() bool {
-	if x, ok := x.GetJsonValue().(*ColumnValue_BoolValue); ok {
-		return x.BoolValue
+func (x *ColumnValue) GetTimestampValue() *timestamppb.Timestamp {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_TimestampValue); ok {
+			return x.TimestampValue
+		}
 	}
-	return false
+	return nil
+}
+
+func (x *ColumnValue) GetDateValue() *Date {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_DateValue); ok {
+			return x.DateValue
+		}
+	}
+	return nil
+}
+
+func (x *ColumnValue) GetTimeValue() *TimeOfDay {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_TimeValue); ok {
+			return x.TimeValue
+		}
+	}
+	return nil
+}
+
+func (x *ColumnValue) GetIntervalValue() *Interval {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_IntervalValue); ok {
+			return x.IntervalValue
+		}
+	}
+	return nil
+}
+
+func (x *ColumnValue) GetNullValue() structpb.NullValue {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_NullValue); ok {
+			return x.NullValue
+		}
+	}
+	return structpb.NullValue(0)
+}
+
+func (x *ColumnValue) GetBytesValue() []byte {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_BytesValue); ok {
+			return x.BytesValue
+		}
+	}
+	return nil
+}
+
+func (x *ColumnValue) GetRangeValue() *Range {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_RangeValue); ok {
+			return x.RangeValue
+		}
+	}
+	return nil
+}
+
+func (x *ColumnValue) GetCompositeValue() *RowWithSchema {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_CompositeValue); ok {
+			return x.CompositeValue
+		}
+	}
+	return nil
+}
+
+func (x *ColumnValue) GetArrayValue() *Array {
+	if x != nil {
+		if x, ok := x.JsonValue.(*ColumnValue_ArrayValue); ok {
+			return x.ArrayValue
+		}
+	}
+	return nil
 }
 
 type isColumnValue_JsonValue interface {
@@ -384,14 +626,60 @@ type ColumnValue_ObjectValue struct {
 	ObjectValue string `protobuf:"bytes,4,opt,name=object_value,json=objectValue,proto3,oneof"`
 }
 
-type ColumnValue_ArrayValue struct {
-	ArrayValue string `protobuf:"bytes,5,opt,name=array_value,json=arrayValue,proto3,oneof"`
-}
-
 type ColumnValue_BoolValue struct {
 	BoolValue bool `protobuf:"varint,6,opt,name=bool_value,json=boolValue,proto3,oneof"`
 }
 
+type ColumnValue_NumberDoubleValue struct {
+	NumberDoubleValue float64 `protobuf:"fixed64,7,opt,name=number_double_value,json=numberDoubleValue,proto3,oneof"`
+}
+
+type ColumnValue_DecimalValue struct {
+	// canonical text of a numeric, e.g. "-123.4500"
+	DecimalValue string `protobuf:"bytes,8,opt,name=decimal_value,json=decimalValue,proto3,oneof"`
+}
+
+type ColumnValue_TimestampValue struct {
+	// timestamp with or without time zone, in UTC
+	TimestampValue *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=timestamp_value,json=timestampValue,proto3,oneof"`
+}
+
+type ColumnValue_DateValue struct {
+	DateValue *Date `protobuf:"bytes,10,opt,name=date_value,json=dateValue,proto3,oneof"`
+}
+
+type ColumnValue_TimeValue struct {
+	TimeValue *TimeOfDay `protobuf:"bytes,11,opt,name=time_value,json=timeValue,proto3,oneof"`
+}
+
+type ColumnValue_IntervalValue struct {
+	IntervalValue *Interval `protobuf:"bytes,12,opt,name=interval_value,json=intervalValue,proto3,oneof"`
+}
+
+type ColumnValue_NullValue struct {
+	// SQL NULL; a column without any value set was not fetched
+	NullValue structpb.NullValue `protobuf:"varint,13,opt,name=null_value,json=nullValue,proto3,enum=google.protobuf.NullValue,oneof"`
+}
+
+type ColumnValue_BytesValue struct {
+	// bytea, base64 encoded in JSON
+	BytesValue []byte `protobuf:"bytes,14,opt,name=bytes_value,json=bytesValue,proto3,oneof"`
+}
+
+type ColumnValue_RangeValue struct {
+	RangeValue *Range `protobuf:"bytes,15,opt,name=range_value,json=rangeValue,proto3,oneof"`
+}
+
+type ColumnValue_CompositeValue struct {
+	// value of a composite type or a row
+	CompositeValue *RowWithSchema `protobuf:"bytes,16,opt,name=composite_value,json=compositeValue,proto3,oneof"`
+}
+
+type ColumnValue_ArrayValue struct {
+	// elements of an array, nested for multidimensional arrays
+	ArrayValue *Array `protobuf:"bytes,17,opt,name=array_value,json=arrayValue,proto3,oneof"`
+}
+
 func (*ColumnValue_StringValue) isColumnValue_JsonValue() {}
 
 func (*ColumnValue_NumberFloatValue) isColumnValue_JsonValue() {}
@@ -400,179 +688,973 @@ func (*ColumnValue_NumberIntegerValue) isColumnValue_JsonValue() {}
 
 func (*ColumnValue_ObjectValue) isColumnValue_JsonValue() {}
 
-func (*ColumnValue_ArrayValue) isColumnValue_JsonValue() {}
-
 func (*ColumnValue_BoolValue) isColumnValue_JsonValue() {}
 
-var File_fieldset_proto protoreflect.FileDescriptor
+func (*ColumnValue_NumberDoubleValue) isColumnValue_JsonValue() {}
 
-var file_fieldset_proto_rawDesc = []byte{
-	0x0a, 0x0e, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x06, 0x61, 0x6e, 0x79, 0x72, 0x6f, 0x77, 0x22, 0xa6, 0x01, 0x0a, 0x06, 0x52, 0x6f, 0x77,
-	0x53, 0x65, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x4e, 0x61,
-	0x6d, 0x65, 0x12, 0x3b, 0x0a, 0x0e, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x5f, 0x73, 0x63, 0x68,
-	0x65, 0x6d, 0x61, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x6e, 0x79,
-	0x72, 0x6f, 0x77, 0x2e, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61,
-	0x52, 0x0d, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x73, 0x12,
-	0x1f, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
-	0x61, 0x6e, 0x79, 0x72, 0x6f, 0x77, 0x2e, 0x52, 0x6f, 0x77, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73,
-	0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x4e, 0x61, 0x6d,
-	0x65, 0x22, 0x6e, 0x0a, 0x0d, 0x52, 0x6f, 0x77, 0x57, 0x69, 0x74, 0x68, 0x53, 0x63, 0x68, 0x65,
-	0x6d, 0x61, 0x12, 0x2e, 0x0a, 0x07, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x6e, 0x79, 0x72, 0x6f, 0x77, 0x2e, 0x43, 0x6f, 0x6c,
-	0x75, 0x6d, 0x6e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x07, 0x73, 0x63, 0x68, 0x65, 0x6d,
-	0x61, 0x73, 0x12, 0x2d, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x6e, 0x79, 0x72, 0x6f, 0x77, 0x2e, 0x43, 0x6f, 0x6c,
-	0x75, 0x6d, 0x6e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
-	0x73, 0x22, 0x85, 0x01, 0x0a, 0x0c, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x53, 0x63, 0x68, 0x65,
-	0x6d, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x79, 0x70, 0x65, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x73, 0x5f, 0x6e, 0x75, 0x6c, 0x6c, 0x61, 0x62,
-	0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x73, 0x4e, 0x75, 0x6c, 0x6c,
-	0x61, 0x62, 0x6c, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x73, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x61,
-	0x72, 0x79, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x73, 0x50,
-	0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x6b, 0x65, 0x79, 0x22, 0x34, 0x0a, 0x03, 0x52, 0x6f, 0x77,
-	0x12, 0x2d, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x13, 0x2e, 0x61, 0x6e, 0x79, 0x72, 0x6f, 0x77, 0x2e, 0x43, 0x6f, 0x6c, 0x75, 0x6d,
-	0x6e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x22,
-	0x8d, 0x02, 0x0a, 0x0b, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12,
-	0x23, 0x0a, 0x0c, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x56,
-	0x61, 0x6c, 0x75, 0x65, 0x12, 0x2e, 0x0a, 0x12, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x66,
-	0x6c, 0x6f, 0x61, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02,
-	0x48, 0x00, 0x52, 0x10, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x56,
-	0x61, 0x6c, 0x75, 0x65, 0x12, 0x32, 0x0a, 0x14, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x69,
-	0x6e, 0x74, 0x65, 0x67, 0x65, 0x72, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x03, 0x48, 0x00, 0x52, 0x12, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x49, 0x6e, 0x74, 0x65,
-	0x67, 0x65, 0x72, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x23, 0x0a, 0x0c, 0x6f, 0x62, 0x6a, 0x65,
-	0x63, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
-	0x52, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a,
-	0x0b, 0x61, 0x72, 0x72, 0x61, 0x79, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x72, 0x72, 0x61, 0x79, 0x56, 0x61, 0x6c, 0x75, 0x65,
-	0x12, 0x1f, 0x0a, 0x0a, 0x62, 0x6f, 0x6f, 0x6c, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x09, 0x62, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75,
-	0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x6a, 0x73, 0x6f, 0x6e, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42,
-	0x05, 0x5a, 0x03, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+func (*ColumnValue_DecimalValue) isColumnValue_JsonValue() {}
 
-var (
-	file_fieldset_proto_rawDescOnce sync.Once
-	file_fieldset_proto_rawDescData = file_fieldset_proto_rawDesc
-)
+func (*ColumnValue_TimestampValue) isColumnValue_JsonValue() {}
 
-func file_fieldset_proto_rawDescGZIP() []byte {
-	file_fieldset_proto_rawDescOnce.Do(func() {
-		file_fieldset_proto_rawDescData = protoimpl.X.CompressGZIP(file_fieldset_proto_rawDescData)
-	})
-	return file_fieldset_proto_rawDescData
+func (*ColumnValue_DateValue) isColumnValue_JsonValue() {}
+
+func (*ColumnValue_TimeValue) isColumnValue_JsonValue() {}
+
+func (*ColumnValue_IntervalValue) isColumnValue_JsonValue() {}
+
+func (*ColumnValue_NullValue) isColumnValue_JsonValue() {}
+
+func (*ColumnValue_BytesValue) isColumnValue_JsonValue() {}
+
+func (*ColumnValue_RangeValue) isColumnValue_JsonValue() {}
+
+func (*ColumnValue_CompositeValue) isColumnValue_JsonValue() {}
+
+func (*ColumnValue_ArrayValue) isColumnValue_JsonValue() {}
+
+// Array holds the elements of an array value.
+type Array struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Elements      []*ColumnValue         `protobuf:"bytes,1,rep,name=elements,proto3" json:"elements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-var file_fieldset_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_fieldset_proto_goTypes = []interface{}{
-	(*RowSet)(nil),        // 0: anyrow.RowSet
-	(*RowWithSchema)(nil), // 1: anyrow.RowWithSchema
-	(*ColumnSchema)(nil),  // 2: anyrow.ColumnSchema
-	(*Row)(nil),           // 3: anyrow.Row
-	(*ColumnValue)(nil),   // 4: anyrow.ColumnValue
+func (x *Array) Reset() {
+	*x = Array{}
+	mi := &file_fieldset_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-var file_fieldset_proto_depIdxs = []int32{
-	2, // 0: anyrow.RowSet.column_schemas:type_name -> anyrow.ColumnSchema
-	3, // 1: anyrow.RowSet.rows:type_name -> anyrow.Row
-	2, // 2: anyrow.RowWithSchema.schemas:type_name -> anyrow.ColumnSchema
-	4, // 3: anyrow.RowWithSchema.columns:type_name -> anyrow.ColumnValue
-	4, // 4: anyrow.Row.columns:type_name -> anyrow.ColumnValue
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+
+func (x *Array) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_fieldset_proto_init() }
-func file_fieldset_proto_init() {
-	if File_fieldset_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_fieldset_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RowSet); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_fieldset_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RowWithSchema); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_fieldset_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ColumnSchema); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_fieldset_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Row); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_fieldset_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ColumnValue); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
-	file_fieldset_proto_msgTypes[4].OneofWrappers = []interface{}{
-		(*ColumnValue_StringValue)(nil),
-		(*ColumnValue_NumberFloatValue)(nil),
-		(*ColumnValue_NumberIntegerValue)(nil),
-		(*ColumnValue_ObjectValue)(nil),
-		(*ColumnValue_ArrayValue)(nil),
+func (*Array) ProtoMessage() {}
+
+func (x *Array) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Array.ProtoReflect.Descriptor instead.
+func (*Array) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Array) GetElements() []*ColumnValue {
+	if x != nil {
+		return x.Elements
+	}
+	return nil
+}
+
+// Range is a value of a range type such as int4range or tstzrange.
+type Range struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// null if unbounded
+	Lower *ColumnValue `protobuf:"bytes,1,opt,name=lower,proto3" json:"lower,omitempty"`
+	// null if unbounded
+	Upper *ColumnValue `protobuf:"bytes,2,opt,name=upper,proto3" json:"upper,omitempty"`
+	// inclusive or exclusive bounds, e.g. "[)"
+	Bounds string `protobuf:"bytes,3,opt,name=bounds,proto3" json:"bounds,omitempty"`
+	// the range has no points; then lower, upper and bounds are not set
+	Empty         bool `protobuf:"varint,4,opt,name=empty,proto3" json:"empty,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Range) Reset() {
+	*x = Range{}
+	mi := &file_fieldset_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Range) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Range) ProtoMessage() {}
+
+func (x *Range) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Range.ProtoReflect.Descriptor instead.
+func (*Range) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Range) GetLower() *ColumnValue {
+	if x != nil {
+		return x.Lower
+	}
+	return nil
+}
+
+func (x *Range) GetUpper() *ColumnValue {
+	if x != nil {
+		return x.Upper
+	}
+	return nil
+}
+
+func (x *Range) GetBounds() string {
+	if x != nil {
+		return x.Bounds
+	}
+	return ""
+}
+
+func (x *Range) GetEmpty() bool {
+	if x != nil {
+		return x.Empty
+	}
+	return false
+}
+
+// Date is a calendar date without time zone.
+type Date struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Year          int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month         int32                  `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	Day           int32                  `protobuf:"varint,3,opt,name=day,proto3" json:"day,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Date) Reset() {
+	*x = Date{}
+	mi := &file_fieldset_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Date) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Date) ProtoMessage() {}
+
+func (x *Date) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Date.ProtoReflect.Descriptor instead.
+func (*Date) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Date) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Date) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *Date) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+// TimeOfDay is a time without date and time zone.
+type TimeOfDay struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// since midnight
+	Microseconds  int64 `protobuf:"varint,1,opt,name=microseconds,proto3" json:"microseconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeOfDay) Reset() {
+	*x = TimeOfDay{}
+	mi := &file_fieldset_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeOfDay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeOfDay) ProtoMessage() {}
+
+func (x *TimeOfDay) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeOfDay.ProtoReflect.Descriptor instead.
+func (*TimeOfDay) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TimeOfDay) GetMicroseconds() int64 {
+	if x != nil {
+		return x.Microseconds
+	}
+	return 0
+}
+
+// Interval is a time span as stored by Postgres; months and days are kept apart because their length varies.
+type Interval struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Microseconds  int64                  `protobuf:"varint,1,opt,name=microseconds,proto3" json:"microseconds,omitempty"`
+	Days          int32                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	Months        int32                  `protobuf:"varint,3,opt,name=months,proto3" json:"months,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Interval) Reset() {
+	*x = Interval{}
+	mi := &file_fieldset_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Interval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Interval) ProtoMessage() {}
+
+func (x *Interval) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Interval.ProtoReflect.Descriptor instead.
+func (*Interval) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Interval) GetMicroseconds() int64 {
+	if x != nil {
+		return x.Microseconds
+	}
+	return 0
+}
+
+func (x *Interval) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *Interval) GetMonths() int32 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+type PageToken struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// values of the key columns of the last row of a page
+	KeyValues     []*ColumnValue `protobuf:"bytes,1,rep,name=key_values,json=keyValues,proto3" json:"key_values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageToken) Reset() {
+	*x = PageToken{}
+	mi := &file_fieldset_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageToken) ProtoMessage() {}
+
+func (x *PageToken) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageToken.ProtoReflect.Descriptor instead.
+func (*PageToken) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PageToken) GetKeyValues() []*ColumnValue {
+	if x != nil {
+		return x.KeyValues
+	}
+	return nil
+}
+
+// ForeignKey is a foreign key constraint from the columns of a table to the columns of a referenced table.
+type ForeignKey struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Name                 string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SchemaName           string                 `protobuf:"bytes,2,opt,name=schema_name,json=schemaName,proto3" json:"schema_name,omitempty"`
+	TableName            string                 `protobuf:"bytes,3,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	Columns              []string               `protobuf:"bytes,4,rep,name=columns,proto3" json:"columns,omitempty"`
+	ReferencedSchemaName string                 `protobuf:"bytes,5,opt,name=referenced_schema_name,json=referencedSchemaName,proto3" json:"referenced_schema_name,omitempty"`
+	ReferencedTableName  string                 `protobuf:"bytes,6,opt,name=referenced_table_name,json=referencedTableName,proto3" json:"referenced_table_name,omitempty"`
+	// in the order of columns
+	ReferencedColumns []string `protobuf:"bytes,7,rep,name=referenced_columns,json=referencedColumns,proto3" json:"referenced_columns,omitempty"`
+	// NO ACTION, RESTRICT, CASCADE, SET NULL or SET DEFAULT
+	OnDelete      string `protobuf:"bytes,8,opt,name=on_delete,json=onDelete,proto3" json:"on_delete,omitempty"`
+	OnUpdate      string `protobuf:"bytes,9,opt,name=on_update,json=onUpdate,proto3" json:"on_update,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForeignKey) Reset() {
+	*x = ForeignKey{}
+	mi := &file_fieldset_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForeignKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForeignKey) ProtoMessage() {}
+
+func (x *ForeignKey) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForeignKey.ProtoReflect.Descriptor instead.
+func (*ForeignKey) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ForeignKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ForeignKey) GetSchemaName() string {
+	if x != nil {
+		return x.SchemaName
+	}
+	return ""
+}
+
+func (x *ForeignKey) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *ForeignKey) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *ForeignKey) GetReferencedSchemaName() string {
+	if x != nil {
+		return x.ReferencedSchemaName
+	}
+	return ""
+}
+
+func (x *ForeignKey) GetReferencedTableName() string {
+	if x != nil {
+		return x.ReferencedTableName
+	}
+	return ""
+}
+
+func (x *ForeignKey) GetReferencedColumns() []string {
+	if x != nil {
+		return x.ReferencedColumns
+	}
+	return nil
+}
+
+func (x *ForeignKey) GetOnDelete() string {
+	if x != nil {
+		return x.OnDelete
+	}
+	return ""
+}
+
+func (x *ForeignKey) GetOnUpdate() string {
+	if x != nil {
+		return x.OnUpdate
+	}
+	return ""
+}
+
+// TableSchema is the metadata of a table: its columns and its table-level indexes and constraints.
+type TableSchema struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SchemaName        string                 `protobuf:"bytes,1,opt,name=schema_name,json=schemaName,proto3" json:"schema_name,omitempty"`
+	TableName         string                 `protobuf:"bytes,2,opt,name=table_name,json=tableName,proto3" json:"table_name,omitempty"`
+	ColumnSchemas     []*ColumnSchema        `protobuf:"bytes,3,rep,name=column_schemas,json=columnSchemas,proto3" json:"column_schemas,omitempty"`
+	Indexes           []*Index               `protobuf:"bytes,4,rep,name=indexes,proto3" json:"indexes,omitempty"`
+	UniqueConstraints []*UniqueConstraint    `protobuf:"bytes,5,rep,name=unique_constraints,json=uniqueConstraints,proto3" json:"unique_constraints,omitempty"`
+	CheckConstraints  []*CheckConstraint     `protobuf:"bytes,6,rep,name=check_constraints,json=checkConstraints,proto3" json:"check_constraints,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *TableSchema) Reset() {
+	*x = TableSchema{}
+	mi := &file_fieldset_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableSchema) ProtoMessage() {}
+
+func (x *TableSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableSchema.ProtoReflect.Descriptor instead.
+func (*TableSchema) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TableSchema) GetSchemaName() string {
+	if x != nil {
+		return x.SchemaName
+	}
+	return ""
+}
+
+func (x *TableSchema) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *TableSchema) GetColumnSchemas() []*ColumnSchema {
+	if x != nil {
+		return x.ColumnSchemas
+	}
+	return nil
+}
+
+func (x *TableSchema) GetIndexes() []*Index {
+	if x != nil {
+		return x.Indexes
+	}
+	return nil
+}
+
+func (x *TableSchema) GetUniqueConstraints() []*UniqueConstraint {
+	if x != nil {
+		return x.UniqueConstraints
+	}
+	return nil
+}
+
+func (x *TableSchema) GetCheckConstraints() []*CheckConstraint {
+	if x != nil {
+		return x.CheckConstraints
+	}
+	return nil
+}
+
+// Index is an index of a table.
+type Index struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// names of the key columns or, for an expression index, their expressions
+	Columns   []string `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
+	IsUnique  bool     `protobuf:"varint,3,opt,name=is_unique,json=isUnique,proto3" json:"is_unique,omitempty"`
+	IsPrimary bool     `protobuf:"varint,4,opt,name=is_primary,json=isPrimary,proto3" json:"is_primary,omitempty"`
+	// WHERE condition of a partial index, empty if not partial
+	Predicate string `protobuf:"bytes,5,opt,name=predicate,proto3" json:"predicate,omitempty"`
+	// access method, e.g. btree, hash, gin or gist
+	Method        string `protobuf:"bytes,6,opt,name=method,proto3" json:"method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Index) Reset() {
+	*x = Index{}
+	mi := &file_fieldset_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Index) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Index) ProtoMessage() {}
+
+func (x *Index) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Index.ProtoReflect.Descriptor instead.
+func (*Index) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Index) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Index) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *Index) GetIsUnique() bool {
+	if x != nil {
+		return x.IsUnique
+	}
+	return false
+}
+
+func (x *Index) GetIsPrimary() bool {
+	if x != nil {
+		return x.IsPrimary
+	}
+	return false
+}
+
+func (x *Index) GetPredicate() string {
+	if x != nil {
+		return x.Predicate
+	}
+	return ""
+}
+
+func (x *Index) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+// UniqueConstraint is a constraint that the values of the columns of a table are unique.
+type UniqueConstraint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Columns       []string               `protobuf:"bytes,2,rep,name=columns,proto3" json:"columns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniqueConstraint) Reset() {
+	*x = UniqueConstraint{}
+	mi := &file_fieldset_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniqueConstraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniqueConstraint) ProtoMessage() {}
+
+func (x *UniqueConstraint) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniqueConstraint.ProtoReflect.Descriptor instead.
+func (*UniqueConstraint) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UniqueConstraint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UniqueConstraint) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+// CheckConstraint is a constraint that each row of a table satisfies a boolean expression.
+type CheckConstraint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Expression    string                 `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckConstraint) Reset() {
+	*x = CheckConstraint{}
+	mi := &file_fieldset_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckConstraint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckConstraint) ProtoMessage() {}
+
+func (x *CheckConstraint) ProtoReflect() protoreflect.Message {
+	mi := &file_fieldset_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckConstraint.ProtoReflect.Descriptor instead.
+func (*CheckConstraint) Descriptor() ([]byte, []int) {
+	return file_fieldset_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CheckConstraint) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CheckConstraint) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+var File_fieldset_proto protoreflect.FileDescriptor
+
+const file_fieldset_proto_rawDesc = "" +
+	"\n" +
+	"\x0efieldset.proto\x12\x06anyrow\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa6\x01\n" +
+	"\x06RowSet\x12\x1d\n" +
+	"\n" +
+	"table_name\x18\x01 \x01(\tR\ttableName\x12;\n" +
+	"\x0ecolumn_schemas\x18\x02 \x03(\v2\x14.anyrow.ColumnSchemaR\rcolumnSchemas\x12\x1f\n" +
+	"\x04rows\x18\x03 \x03(\v2\v.anyrow.RowR\x04rows\x12\x1f\n" +
+	"\vschema_name\x18\x04 \x01(\tR\n" +
+	"schemaName\"n\n" +
+	"\rRowWithSchema\x12.\n" +
+	"\aschemas\x18\x01 \x03(\v2\x14.anyrow.ColumnSchemaR\aschemas\x12-\n" +
+	"\acolumns\x18\x02 \x03(\v2\x13.anyrow.ColumnValueR\acolumns\"\x8c\x06\n" +
+	"\fColumnSchema\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\ttype_name\x18\x02 \x01(\tR\btypeName\x12\x1f\n" +
+	"\vis_nullable\x18\x03 \x01(\bR\n" +
+	"isNullable\x12#\n" +
+	"\ris_primarykey\x18\x04 \x01(\bR\fisPrimarykey\x12\x19\n" +
+	"\btype_oid\x18\x05 \x01(\rR\atypeOid\x12\x19\n" +
+	"\budt_name\x18\x06 \x01(\tR\audtName\x12\x1f\n" +
+	"\vdomain_name\x18\a \x01(\tR\n" +
+	"domainName\x12\x1f\n" +
+	"\venum_labels\x18\b \x03(\tR\n" +
+	"enumLabels\x12A\n" +
+	"\x11attribute_schemas\x18\t \x03(\v2\x14.anyrow.ColumnSchemaR\x10attributeSchemas\x12)\n" +
+	"\x10ordinal_position\x18\n" +
+	" \x01(\x05R\x0fordinalPosition\x12%\n" +
+	"\x0ecolumn_default\x18\v \x01(\tR\rcolumnDefault\x128\n" +
+	"\x18character_maximum_length\x18\f \x01(\x05R\x16characterMaximumLength\x12+\n" +
+	"\x11numeric_precision\x18\r \x01(\x05R\x10numericPrecision\x12#\n" +
+	"\rnumeric_scale\x18\x0e \x01(\x05R\fnumericScale\x12\x1f\n" +
+	"\vis_identity\x18\x0f \x01(\bR\n" +
+	"isIdentity\x12/\n" +
+	"\x13identity_generation\x18\x10 \x01(\tR\x12identityGeneration\x12!\n" +
+	"\fis_generated\x18\x11 \x01(\bR\visGenerated\x123\n" +
+	"\x15generation_expression\x18\x12 \x01(\tR\x14generationExpression\x12\x18\n" +
+	"\acomment\x18\x13 \x01(\tR\acomment\x12(\n" +
+	"\x10element_type_oid\x18\x14 \x01(\rR\x0eelementTypeOid\"4\n" +
+	"\x03Row\x12-\n" +
+	"\acolumns\x18\x01 \x03(\v2\x13.anyrow.ColumnValueR\acolumns\"\xb4\x06\n" +
+	"\vColumnValue\x12#\n" +
+	"\fstring_value\x18\x01 \x01(\tH\x00R\vstringValue\x12.\n" +
+	"\x12number_float_value\x18\x02 \x01(\x02H\x00R\x10numberFloatValue\x122\n" +
+	"\x14number_integer_value\x18\x03 \x01(\x03H\x00R\x12numberIntegerValue\x12#\n" +
+	"\fobject_value\x18\x04 \x01(\tH\x00R\vobjectValue\x12\x1f\n" +
+	"\n" +
+	"bool_value\x18\x06 \x01(\bH\x00R\tboolValue\x120\n" +
+	"\x13number_double_value\x18\a \x01(\x01H\x00R\x11numberDoubleValue\x12%\n" +
+	"\rdecimal_value\x18\b \x01(\tH\x00R\fdecimalValue\x12E\n" +
+	"\x0ftimestamp_value\x18\t \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x0etimestampValue\x12-\n" +
+	"\n" +
+	"date_value\x18\n" +
+	" \x01(\v2\f.anyrow.DateH\x00R\tdateValue\x122\n" +
+	"\n" +
+	"time_value\x18\v \x01(\v2\x11.anyrow.TimeOfDayH\x00R\ttimeValue\x129\n" +
+	"\x0einterval_value\x18\f \x01(\v2\x10.anyrow.IntervalH\x00R\rintervalValue\x12;\n" +
+	"\n" +
+	"null_value\x18\r \x01(\x0e2\x1a.google.protobuf.NullValueH\x00R\tnullValue\x12!\n" +
+	"\vbytes_value\x18\x0e \x01(\fH\x00R\n" +
+	"bytesValue\x120\n" +
+	"\vrange_value\x18\x0f \x01(\v2\r.anyrow.RangeH\x00R\n" +
+	"rangeValue\x12@\n" +
+	"\x0fcomposite_value\x18\x10 \x01(\v2\x15.anyrow.RowWithSchemaH\x00R\x0ecompositeValue\x120\n" +
+	"\varray_value\x18\x11 \x01(\v2\r.anyrow.ArrayH\x00R\n" +
+	"arrayValueB\f\n" +
+	"\n" +
+	"json_valueJ\x04\b\x05\x10\x06\"8\n" +
+	"\x05Array\x12/\n" +
+	"\belements\x18\x01 \x03(\v2\x13.anyrow.ColumnValueR\belements\"\x8b\x01\n" +
+	"\x05Range\x12)\n" +
+	"\x05lower\x18\x01 \x01(\v2\x13.anyrow.ColumnValueR\x05lower\x12)\n" +
+	"\x05upper\x18\x02 \x01(\v2\x13.anyrow.ColumnValueR\x05upper\x12\x16\n" +
+	"\x06bounds\x18\x03 \x01(\tR\x06bounds\x12\x14\n" +
+	"\x05empty\x18\x04 \x01(\bR\x05empty\"B\n" +
+	"\x04Date\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05month\x18\x02 \x01(\x05R\x05month\x12\x10\n" +
+	"\x03day\x18\x03 \x01(\x05R\x03day\"/\n" +
+	"\tTimeOfDay\x12\"\n" +
+	"\fmicroseconds\x18\x01 \x01(\x03R\fmicroseconds\"Z\n" +
+	"\bInterval\x12\"\n" +
+	"\fmicroseconds\x18\x01 \x01(\x03R\fmicroseconds\x12\x12\n" +
+	"\x04days\x18\x02 \x01(\x05R\x04days\x12\x16\n" +
+	"\x06months\x18\x03 \x01(\x05R\x06months\"?\n" +
+	"\tPageToken\x122\n" +
+	"\n" +
+	"key_values\x18\x01 \x03(\v2\x13.anyrow.ColumnValueR\tkeyValues\"\xcd\x02\n" +
+	"\n" +
+	"ForeignKey\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
+	"\vschema_name\x18\x02 \x01(\tR\n" +
+	"schemaName\x12\x1d\n" +
+	"\n" +
+	"table_name\x18\x03 \x01(\tR\ttableName\x12\x18\n" +
+	"\acolumns\x18\x04 \x03(\tR\acolumns\x124\n" +
+	"\x16referenced_schema_name\x18\x05 \x01(\tR\x14referencedSchemaName\x122\n" +
+	"\x15referenced_table_name\x18\x06 \x01(\tR\x13referencedTableName\x12-\n" +
+	"\x12referenced_columns\x18\a \x03(\tR\x11referencedColumns\x12\x1b\n" +
+	"\ton_delete\x18\b \x01(\tR\bonDelete\x12\x1b\n" +
+	"\ton_update\x18\t \x01(\tR\bonUpdate\"\xc2\x02\n" +
+	"\vTableSchema\x12\x1f\n" +
+	"\vschema_name\x18\x01 \x01(\tR\n" +
+	"schemaName\x12\x1d\n" +
+	"\n" +
+	"table_name\x18\x02 \x01(\tR\ttableName\x12;\n" +
+	"\x0ecolumn_schemas\x18\x03 \x03(\v2\x14.anyrow.ColumnSchemaR\rcolumnSchemas\x12'\n" +
+	"\aindexes\x18\x04 \x03(\v2\r.anyrow.IndexR\aindexes\x12G\n" +
+	"\x12unique_constraints\x18\x05 \x03(\v2\x18.anyrow.UniqueConstraintR\x11uniqueConstraints\x12D\n" +
+	"\x11check_constraints\x18\x06 \x03(\v2\x17.anyrow.CheckConstraintR\x10checkConstraints\"\xa7\x01\n" +
+	"\x05Index\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acolumns\x18\x02 \x03(\tR\acolumns\x12\x1b\n" +
+	"\tis_unique\x18\x03 \x01(\bR\bisUnique\x12\x1d\n" +
+	"\n" +
+	"is_primary\x18\x04 \x01(\bR\tisPrimary\x12\x1c\n" +
+	"\tpredicate\x18\x05 \x01(\tR\tpredicate\x12\x16\n" +
+	"\x06method\x18\x06 \x01(\tR\x06method\"@\n" +
+	"\x10UniqueConstraint\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acolumns\x18\x02 \x03(\tR\acolumns\"E\n" +
+	"\x0fCheckConstraint\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x02 \x01(\tR\n" +
+	"expressionB\x05Z\x03/pbb\x06proto3"
+
+var (
+	file_fieldset_proto_rawDescOnce sync.Once
+	file_fieldset_proto_rawDescData []byte
+)
+
+func file_fieldset_proto_rawDescGZIP() []byte {
+	file_fieldset_proto_rawDescOnce.Do(func() {
+		file_fieldset_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_fieldset_proto_rawDesc), len(file_fieldset_proto_rawDesc)))
+	})
+	return file_fieldset_proto_rawDescData
+}
+
+var file_fieldset_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_fieldset_proto_goTypes = []any{
+	(*RowSet)(nil),                // 0: anyrow.RowSet
+	(*RowWithSchema)(nil),         // 1: anyrow.RowWithSchema
+	(*ColumnSchema)(nil),          // 2: anyrow.ColumnSchema
+	(*Row)(nil),                   // 3: anyrow.Row
+	(*ColumnValue)(nil),           // 4: anyrow.ColumnValue
+	(*Array)(nil),                 // 5: anyrow.Array
+	(*Range)(nil),                 // 6: anyrow.Range
+	(*Date)(nil),                  // 7: anyrow.Date
+	(*TimeOfDay)(nil),             // 8: anyrow.TimeOfDay
+	(*Interval)(nil),              // 9: anyrow.Interval
+	(*PageToken)(nil),             // 10: anyrow.PageToken
+	(*ForeignKey)(nil),            // 11: anyrow.ForeignKey
+	(*TableSchema)(nil),           // 12: anyrow.TableSchema
+	(*Index)(nil),                 // 13: anyrow.Index
+	(*UniqueConstraint)(nil),      // 14: anyrow.UniqueConstraint
+	(*CheckConstraint)(nil),       // 15: anyrow.CheckConstraint
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+	(structpb.NullValue)(0),       // 17: google.protobuf.NullValue
+}
+var file_fieldset_proto_depIdxs = []int32{
+	2,  // 0: anyrow.RowSet.column_schemas:type_name -> anyrow.ColumnSchema
+	3,  // 1: anyrow.RowSet.rows:type_name -> anyrow.Row
+	2,  // 2: anyrow.RowWithSchema.schemas:type_name -> anyrow.ColumnSchema
+	4,  // 3: anyrow.RowWithSchema.columns:type_name -> anyrow.ColumnValue
+	2,  // 4: anyrow.ColumnSchema.attribute_schemas:type_name -> anyrow.ColumnSchema
+	4,  // 5: anyrow.Row.columns:type_name -> anyrow.ColumnValue
+	16, // 6: anyrow.ColumnValue.timestamp_value:type_name -> google.protobuf.Timestamp
+	7,  // 7: anyrow.ColumnValue.date_value:type_name -> anyrow.Date
+	8,  // 8: anyrow.ColumnValue.time_value:type_name -> anyrow.TimeOfDay
+	9,  // 9: anyrow.ColumnValue.interval_value:type_name -> anyrow.Interval
+	17, // 10: anyrow.ColumnValue.null_value:type_name -> google.protobuf.NullValue
+	6,  // 11: anyrow.ColumnValue.range_value:type_name -> anyrow.Range
+	1,  // 12: anyrow.ColumnValue.composite_value:type_name -> anyrow.RowWithSchema
+	5,  // 13: anyrow.ColumnValue.array_value:type_name -> anyrow.Array
+	4,  // 14: anyrow.Array.elements:type_name -> anyrow.ColumnValue
+	4,  // 15: anyrow.Range.lower:type_name -> anyrow.ColumnValue
+	4,  // 16: anyrow.Range.upper:type_name -> anyrow.ColumnValue
+	4,  // 17: anyrow.PageToken.key_values:type_name -> anyrow.ColumnValue
+	2,  // 18: anyrow.TableSchema.column_schemas:type_name -> anyrow.ColumnSchema
+	13, // 19: anyrow.TableSchema.indexes:type_name -> anyrow.Index
+	14, // 20: anyrow.TableSchema.unique_constraints:type_name -> anyrow.UniqueConstraint
+	15, // 21: anyrow.TableSchema.check_constraints:type_name -> anyrow.CheckConstraint
+	22, // [22:22] is the sub-list for method output_type
+	22, // [22:22] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
+}
+
+func init() { file_fieldset_proto_init() }
+func file_fieldset_proto_init() {
+	if File_fieldset_proto != nil {
+		return
+	}
+	file_fieldset_proto_msgTypes[4].OneofWrappers = []any{
+		(*ColumnValue_StringValue)(nil),
+		(*ColumnValue_NumberFloatValue)(nil),
+		(*ColumnValue_NumberIntegerValue)(nil),
+		(*ColumnValue_ObjectValue)(nil),
 		(*ColumnValue_BoolValue)(nil),
+		(*ColumnValue_NumberDoubleValue)(nil),
+		(*ColumnValue_DecimalValue)(nil),
+		(*ColumnValue_TimestampValue)(nil),
+		(*ColumnValue_DateValue)(nil),
+		(*ColumnValue_TimeValue)(nil),
+		(*ColumnValue_IntervalValue)(nil),
+		(*ColumnValue_NullValue)(nil),
+		(*ColumnValue_BytesValue)(nil),
+		(*ColumnValue_RangeValue)(nil),
+		(*ColumnValue_CompositeValue)(nil),
+		(*ColumnValue_ArrayValue)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_fieldset_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_fieldset_proto_rawDesc), len(file_fieldset_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -581,7 +1663,6 @@ func file_fieldset_proto_init() {
 		MessageInfos:      file_fieldset_proto_msgTypes,
 	}.Build()
 	File_fieldset_proto = out.File
-	file_fieldset_proto_rawDesc = nil
 	file_fieldset_proto_goTypes = nil
 	file_fieldset_proto_depIdxs = nil
 }
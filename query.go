@@ -0,0 +1,80 @@
+package anyrow
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// typeNames maps type names of pgtype to those used by information_schema.
+var typeNames = map[string]string{
+	"bool":        "boolean",
+	"bpchar":      "character",
+	"float4":      "real",
+	"float8":      "double precision",
+	"int2":        "smallint",
+	"int4":        "integer",
+	"int8":        "bigint",
+	"time":        "time without time zone",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"varbit":      "bit varying",
+	"varchar":     "character varying",
+}
+
+var defaultTypeMap = pgtype.NewMap()
+
+// FetchQuery runs any SELECT query and returns its rows as a protobuf RowSet.
+// The column schemas are derived from the fields of the result; nullability and primary keys are unknown.
+func FetchQuery(ctx context.Context, conn Querier, sql string, args ...any) (*pb.RowSet, error) {
+	slog.Debug("FetchQuery", "sql", sql, "params", args)
+	dbrows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	set := new(pb.RowSet)
+	for _, each := range dbrows.FieldDescriptions() {
+		set.ColumnSchemas = append(set.ColumnSchemas, &pb.ColumnSchema{
+			Name:       each.Name,
+			TypeName:   typeNameForOID(each.DataTypeOID),
+			IsNullable: true,
+			TypeOid:    each.DataTypeOID,
+			UdtName:    udtNameForOID(each.DataTypeOID),
+		})
+	}
+	collector := &rowsetCollector{
+		set: set,
+	}
+	if err := collectValues(dbrows, set, collector); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// udtNameForOID returns the internal name of a known data type or "unknown".
+func udtNameForOID(oid uint32) string {
+	typ, ok := defaultTypeMap.TypeForOID(oid)
+	if !ok {
+		return "unknown"
+	}
+	return typ.Name
+}
+
+// typeNameForOID returns the name of a known data type or "unknown".
+func typeNameForOID(oid uint32) string {
+	typ, ok := defaultTypeMap.TypeForOID(oid)
+	if !ok {
+		return "unknown"
+	}
+	if strings.HasPrefix(typ.Name, "_") {
+		// names of array types start with an underscore
+		return "ARRAY"
+	}
+	if name, ok := typeNames[typ.Name]; ok {
+		return name
+	}
+	return typ.Name
+}
@@ -0,0 +1,51 @@
+package anyrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestFetchQuery(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{
+		values: []any{"shoe", int32(42), float64(1.5)},
+		fields: []pgconn.FieldDescription{
+			{Name: "name", DataTypeOID: pgtype.TextOID},
+			{Name: "size", DataTypeOID: pgtype.Int4OID},
+			{Name: "price", DataTypeOID: pgtype.Float8OID},
+		},
+	}
+	set, err := FetchQuery(ctx, conn, "SELECT p.name, s.size, s.price FROM products p JOIN sizes s ON s.product_id = p.id WHERE p.id = $1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := set.ColumnSchemas[1].TypeName, "integer"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := set.ColumnSchemas[2].TypeName, "double precision"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	mp := set.RowMap(0)
+	if got, want := mp["name"], "shoe"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := mp["size"], int64(42); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestTypeNameForOID(t *testing.T) {
+	for oid, want := range map[uint32]string{
+		pgtype.UUIDOID:        "uuid",
+		pgtype.TimestamptzOID: "timestamp with time zone",
+		pgtype.Int8ArrayOID:   "ARRAY",
+		999999:                "unknown",
+	} {
+		if got := typeNameForOID(oid); got != want {
+			t.Errorf("got [%v] want [%v]", got, want)
+		}
+	}
+}
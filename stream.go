@@ -0,0 +1,81 @@
+package anyrow
+
+import (
+	"context"
+	"iter"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+// StreamRecords queries a table using a WHERE clause and yields each row as a Record as soon as it is read.
+// Unlike FilterRecords, there is no limit unless the FilterLimit option is given.
+// The query is closed when the consumer stops the iteration or the context is done.
+func StreamRecords(ctx context.Context, conn Querier, metadataCacheKey, tableName string, where string, options ...filterOption) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		filter := newFetchFilter(where, 0, options)
+		set, err = filter.project(set)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		collector := &objectCollector{
+			set: set,
+		}
+		err = streamFiltered(ctx, conn, set, filter, collector, func() bool {
+			// do not accumulate
+			collector.list = nil
+			return yield(collector.object, nil)
+		})
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// StreamRows queries a table using a WHERE clause and yields each row as a pb.Row as soon as it is read.
+// The columns of each Row are in the order of the column schemas returned by FetchColumns, or as selected by FilterColumns.
+// Unlike FilterRecords, there is no limit unless the FilterLimit option is given.
+// The query is closed when the consumer stops the iteration or the context is done.
+func StreamRows(ctx context.Context, conn Querier, metadataCacheKey, tableName string, where string, options ...filterOption) iter.Seq2[*pb.Row, error] {
+	return func(yield func(*pb.Row, error) bool) {
+		set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		filter := newFetchFilter(where, 0, options)
+		set, err = filter.project(set)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		collector := &rowsetCollector{
+			set: &pb.RowSet{
+				SchemaName:    set.SchemaName,
+				TableName:     set.TableName,
+				ColumnSchemas: set.ColumnSchemas,
+			},
+		}
+		err = streamFiltered(ctx, conn, set, filter, collector, func() bool {
+			// do not accumulate
+			collector.set.Rows = nil
+			return yield(collector.row, nil)
+		})
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func streamFiltered(ctx context.Context, conn Querier, set *pb.RowSet, filter fetchFilter, collector valueCollector, rowDone func() bool) error {
+	dbrows, err := queryValues(ctx, conn, set, filter)
+	if err != nil {
+		return err
+	}
+	return streamValues(ctx, dbrows, set, collector, rowDone)
+}
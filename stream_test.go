@@ -0,0 +1,61 @@
+package anyrow
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStreamRecords(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	count := 0
+	for each, err := range StreamRecords(ctx, conn, "testkey", "test", "id > 1") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := each["str"], "shoesize"; got != want {
+			t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+		}
+		count++
+	}
+	if got, want := count, 1; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.sql, `SELECT "str","num" FROM public.test WHERE id > 1`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if !conn.rows.closed {
+		t.Error("rows not closed")
+	}
+}
+
+func TestStreamRowsBreak(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	for each, err := range StreamRows(ctx, conn, "testkey", "test", "", FilterLimit(10)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := each.Columns[0].GetStringValue(), "shoesize"; got != want {
+			t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+		}
+		break
+	}
+	if got, want := conn.sql, `SELECT "str","num" FROM public.test WHERE true LIMIT 10`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if !conn.rows.closed {
+		t.Error("rows not closed")
+	}
+}
+
+func TestStreamRecordsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	conn := new(mockQuerier)
+	for _, err := range StreamRecords(ctx, conn, "testkey", "test", "") {
+		if err != context.Canceled {
+			t.Errorf("got [%v] want [%v]", err, context.Canceled)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package anyrow
+
+import (
+	"context"
+	"slices"
+
+	"github.com/emicklei/anyrow/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// subsetChunkSize is the maximum number of key values to query the rows of a table with at once.
+var subsetChunkSize = 1000
+
+type subsetOption func(c subsetConfig) subsetConfig
+
+type subsetConfig struct {
+	depth   int
+	include []string
+	exclude []string
+}
+
+// SubsetDepth sets how many levels of child rows, those referencing the rows of a table, are extracted.
+// Without this option, no child rows are extracted. Parent rows are always extracted.
+func SubsetDepth(depth int) subsetOption {
+	return func(c subsetConfig) subsetConfig {
+		c.depth = depth
+		return c
+	}
+}
+
+// SubsetInclude limits the tables of which child rows are extracted.
+func SubsetInclude(tableNames ...string) subsetOption {
+	return func(c subsetConfig) subsetConfig {
+		for _, each := range tableNames {
+			c.include = append(c.include, qualifiedName(splitTableName(each)))
+		}
+		return c
+	}
+}
+
+// SubsetExclude excludes tables from the extraction; their rows are neither extracted nor followed.
+// The subset is then no longer closed under the foreign keys to these tables.
+func SubsetExclude(tableNames ...string) subsetOption {
+	return func(c subsetConfig) subsetConfig {
+		for _, each := range tableNames {
+			c.exclude = append(c.exclude, qualifiedName(splitTableName(each)))
+		}
+		return c
+	}
+}
+
+// ExtractSubset returns the rows of a table for the given primary key values together with all rows they reference
+// through foreign keys, transitively, such that the subset is closed under referential integrity.
+// With the SubsetDepth option, rows that reference extracted rows are extracted too, up to that number of levels.
+// The result maps the qualified name of each table to its rows.
+// The metadata of the tables other than the root table is cached by their qualified name.
+func ExtractSubset(ctx context.Context, conn Querier, metadataCacheKey, tableName string, pkv PrimaryKeysAndValues, options ...subsetOption) (map[string]*pb.RowSet, error) {
+	cfg := subsetConfig{}
+	for _, each := range options {
+		cfg = each(cfg)
+	}
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return nil, err
+	}
+	x := &subsetExtraction{
+		ctx:         ctx,
+		conn:        conn,
+		cfg:         cfg,
+		sets:        map[string]*pb.RowSet{},
+		seen:        map[string]map[string]bool{},
+		foreignKeys: map[string][2][]*pb.ForeignKey{},
+	}
+	root := qualifiedName(set.SchemaName, set.TableName)
+	rows, err := x.fetch(set, fetchFilter{pkv: pkv})
+	if err != nil {
+		return nil, err
+	}
+	queue := []subsetBatch{{tableName: root, rows: rows, depth: 0}}
+	for len(queue) > 0 {
+		batch := queue[0]
+		queue = queue[1:]
+		more, err := x.follow(batch)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, more...)
+	}
+	return x.sets, nil
+}
+
+// subsetBatch holds rows of a table that were added to the subset and of which the foreign keys are not yet followed.
+type subsetBatch struct {
+	tableName string
+	rows      []*pb.Row
+	// number of levels of child rows from the root table
+	depth int
+}
+
+type subsetExtraction struct {
+	ctx  context.Context
+	conn Querier
+	cfg  subsetConfig
+	// rows by qualified table name
+	sets map[string]*pb.RowSet
+	// keys of the rows by qualified table name
+	seen map[string]map[string]bool
+	// outgoing and incoming foreign keys by qualified table name
+	foreignKeys map[string][2][]*pb.ForeignKey
+}
+
+// follow fetches the parent rows and, if not too deep, the child rows of the rows of a batch.
+// It returns the batches of rows that were added.
+func (x *subsetExtraction) follow(batch subsetBatch) ([]subsetBatch, error) {
+	outgoing, incoming, err := x.foreignKeysOf(batch.tableName)
+	if err != nil {
+		return nil, err
+	}
+	set := x.sets[batch.tableName]
+	added := []subsetBatch{}
+	for _, each := range outgoing {
+		parent := qualifiedName(each.ReferencedSchemaName, each.ReferencedTableName)
+		if slices.Contains(x.cfg.exclude, parent) {
+			continue
+		}
+		rows, err := x.fetchMatching(parent, each.ReferencedColumns, keyValues(set, batch.rows, each.Columns))
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > 0 {
+			// children of parent rows are not followed
+			added = append(added, subsetBatch{tableName: parent, rows: rows, depth: x.cfg.depth})
+		}
+	}
+	if batch.depth >= x.cfg.depth {
+		return added, nil
+	}
+	for _, each := range incoming {
+		child := qualifiedName(each.SchemaName, each.TableName)
+		if slices.Contains(x.cfg.exclude, child) {
+			continue
+		}
+		if len(x.cfg.include) > 0 && !slices.Contains(x.cfg.include, child) {
+			continue
+		}
+		rows, err := x.fetchMatching(child, each.Columns, keyValues(set, batch.rows, each.ReferencedColumns))
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > 0 {
+			added = append(added, subsetBatch{tableName: child, rows: rows, depth: batch.depth + 1})
+		}
+	}
+	return added, nil
+}
+
+// foreignKeysOf returns the outgoing and incoming foreign keys of a table, queried once per table.
+func (x *subsetExtraction) foreignKeysOf(tableName string) (outgoing, incoming []*pb.ForeignKey, err error) {
+	if keys, ok := x.foreignKeys[tableName]; ok {
+		return keys[0], keys[1], nil
+	}
+	outgoing, incoming, err = getForeignKeys(x.ctx, x.conn, tableName)
+	if err != nil {
+		return nil, nil, err
+	}
+	x.foreignKeys[tableName] = [2][]*pb.ForeignKey{outgoing, incoming}
+	return outgoing, incoming, nil
+}
+
+// fetchMatching fetches the rows of a table for which the columns have one of the tuples of values.
+// It returns the rows that were not yet part of the subset.
+func (x *subsetExtraction) fetchMatching(tableName string, columns []string, tuples [][]any) ([]*pb.Row, error) {
+	if len(tuples) == 0 {
+		return nil, nil
+	}
+	set, err := cachedMetadata(x.ctx, x.conn, tableName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	added := []*pb.Row{}
+	for chunk := range slices.Chunk(tuples, subsetChunkSize) {
+		where := matchingExpr(columns, chunk)
+		rows, err := x.fetch(set, fetchFilter{expr: &where})
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, rows...)
+	}
+	return added, nil
+}
+
+// fetch fetches the rows of a table matching the filter and adds those that were not yet part of the subset.
+// It returns the added rows.
+func (x *subsetExtraction) fetch(metaSet *pb.RowSet, filter fetchFilter) ([]*pb.Row, error) {
+	collector := &rowsetCollector{
+		set: &pb.RowSet{
+			SchemaName:    metaSet.SchemaName,
+			TableName:     metaSet.TableName,
+			ColumnSchemas: metaSet.ColumnSchemas,
+		},
+	}
+	if err := fetchValues(x.ctx, x.conn, metaSet, filter, collector); err != nil {
+		return nil, err
+	}
+	tableName := qualifiedName(metaSet.SchemaName, metaSet.TableName)
+	set, ok := x.sets[tableName]
+	if !ok {
+		set = &pb.RowSet{
+			SchemaName:    metaSet.SchemaName,
+			TableName:     metaSet.TableName,
+			ColumnSchemas: metaSet.ColumnSchemas,
+		}
+		x.sets[tableName] = set
+		x.seen[tableName] = map[string]bool{}
+	}
+	seen := x.seen[tableName]
+	added := []*pb.Row{}
+	for _, each := range collector.set.Rows {
+		key := rowKey(set, each)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		set.Rows = append(set.Rows, each)
+		added = append(added, each)
+	}
+	return added, nil
+}
+
+// matchingExpr returns the condition for rows of which the columns have one of the tuples of values.
+func matchingExpr(columns []string, tuples [][]any) Expr {
+	if len(columns) == 1 {
+		values := make([]any, len(tuples))
+		for i, each := range tuples {
+			values[i] = each[0]
+		}
+		return In(columns[0], values...)
+	}
+	alternatives := make([]Expr, len(tuples))
+	for i, each := range tuples {
+		equals := make([]Expr, len(columns))
+		for c, column := range columns {
+			equals[c] = Eq(column, each[c])
+		}
+		alternatives[i] = And(equals...)
+	}
+	return Or(alternatives...)
+}
+
+// keyValues returns the distinct tuples of values of the columns of the rows, skipping tuples with a NULL value.
+func keyValues(set *pb.RowSet, rows []*pb.Row, columns []string) [][]any {
+	indices := make([]int, len(columns))
+	for i, each := range columns {
+		indices[i] = slices.IndexFunc(set.ColumnSchemas, func(s *pb.ColumnSchema) bool { return s.Name == each })
+		if indices[i] < 0 {
+			return nil
+		}
+	}
+	tuples := [][]any{}
+	distinct := map[string]bool{}
+	for _, row := range rows {
+		cells := make([]*pb.ColumnValue, len(indices))
+		tuple := make([]any, len(indices))
+		for i, index := range indices {
+			cells[i] = row.Columns[index]
+			tuple[i] = cells[i].Value()
+		}
+		if slices.Contains(tuple, nil) {
+			continue
+		}
+		key := columnsKey(cells)
+		if distinct[key] {
+			continue
+		}
+		distinct[key] = true
+		tuples = append(tuples, tuple)
+	}
+	return tuples
+}
+
+// rowKey returns a string that identifies the row by its primary key values or, without a primary key, by all its values.
+func rowKey(set *pb.RowSet, row *pb.Row) string {
+	cells := []*pb.ColumnValue{}
+	for i, each := range set.ColumnSchemas {
+		if each.IsPrimarykey {
+			cells = append(cells, row.Columns[i])
+		}
+	}
+	if len(cells) == 0 {
+		cells = row.Columns
+	}
+	return columnsKey(cells)
+}
+
+// columnsKey returns a string that is equal for equal column values, being their deterministic wire format.
+func columnsKey(cells []*pb.ColumnValue) string {
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(&pb.Row{Columns: cells})
+	return string(data)
+}
@@ -0,0 +1,115 @@
+package anyrow
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+func setupLineKeys() {
+	metaCache.Set("public.orders", &pb.RowSet{SchemaName: "public", TableName: "orders", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "customer_id", TypeName: "bigint", IsNullable: true},
+	}}, 0)
+	metaCache.Set("public.lines", &pb.RowSet{SchemaName: "public", TableName: "lines", ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "order_id", TypeName: "bigint", IsPrimarykey: true},
+		{Name: "nr", TypeName: "integer", IsPrimarykey: true},
+		{Name: "product", TypeName: "text", IsNullable: true},
+	}}, 0)
+}
+
+func TestExtractSubset(t *testing.T) {
+	setupOrderKeys()
+	setupLineKeys()
+	lineOrderKey := []any{"lines_order_fk", "public", "lines", []string{"order_id"}, "public", "orders", []string{"id"}, "a", "a", false, true}
+	conn := &mockQuerier{results: [][][]any{
+		// root order
+		{{int64(1), int64(7)}},
+		// foreign keys of orders
+		{orderCustomerKey(true, false), lineOrderKey},
+		// parent customer
+		{{int64(7), "acme"}},
+		// child lines
+		{{int64(1), int64(1), "bolt"}, {int64(1), int64(2), "nut"}},
+		// foreign keys of customers
+		{orderCustomerKey(false, true)},
+		// foreign keys of lines
+		{{"lines_order_fk", "public", "lines", []string{"order_id"}, "public", "orders", []string{"id"}, "a", "a", true, false}},
+		// parent order of lines, already extracted
+		{{int64(1), int64(7)}},
+	}}
+	subset, err := ExtractSubset(context.Background(), conn, "testorderkey", "orders", NewPrimaryKeyAndValues("id", int64(1)), SubsetDepth(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(conn.sqls), 7; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.sqls[2], `SELECT "id","name" FROM public.customers WHERE "id" IN ($1)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.sqls[3], `SELECT "order_id","nr","product" FROM public.lines WHERE "order_id" IN ($1)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	counts := map[string]int{}
+	for k, v := range subset {
+		counts[k] = len(v.Rows)
+	}
+	if got, want := counts, map[string]int{"public.orders": 1, "public.customers": 1, "public.lines": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestExtractSubsetExclude(t *testing.T) {
+	setupOrderKeys()
+	conn := &mockQuerier{results: [][][]any{
+		{{int64(1), int64(7)}},
+		{orderCustomerKey(true, false)},
+	}}
+	subset, err := ExtractSubset(context.Background(), conn, "testorderkey", "orders", NewPrimaryKeyAndValues("id", int64(1)), SubsetExclude("customers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(conn.sqls), 2; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if _, ok := subset["public.customers"]; ok {
+		t.Error("customers not expected")
+	}
+}
+
+func TestMatchingExpr(t *testing.T) {
+	b := new(strings.Builder)
+	args := matchingExpr([]string{"a", "b"}, [][]any{{1, 2}, {3, 4}}).writeOn(b, nil)
+	if got, want := b.String(), `(("a" = $1 AND "b" = $2) OR ("a" = $3 AND "b" = $4))`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := args, []any{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestRowKey(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{{Name: "day"}, {Name: "at"}}}
+	row := func(day int32, micros int64) *pb.Row {
+		return &pb.Row{Columns: []*pb.ColumnValue{
+			{JsonValue: &pb.ColumnValue_DateValue{DateValue: &pb.Date{Year: 2024, Month: 2, Day: day}}},
+			{JsonValue: &pb.ColumnValue_TimeValue{TimeValue: &pb.TimeOfDay{Microseconds: micros}}},
+		}}
+	}
+	first, same, other := row(29, 1), row(29, 1), row(28, 1)
+	// accessing a message may change its internal state but not its key
+	_ = first.String()
+	if got, want := rowKey(set, first), rowKey(set, same); got != want {
+		t.Errorf("got [%q] want [%q]", got, want)
+	}
+	if rowKey(set, first) == rowKey(set, other) {
+		t.Error("different keys expected")
+	}
+	if got, want := len(keyValues(set, []*pb.Row{first, same, other}, []string{"day", "at"})), 2; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
@@ -0,0 +1,208 @@
+package anyrow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/patrickmn/go-cache"
+)
+
+// FetchTableSchema returns the column schemas of a table together with its indexes, unique constraints and check constraints.
+func FetchTableSchema(ctx context.Context, conn Querier, tableName string) (*pb.TableSchema, error) {
+	set, err := getMetadata(ctx, conn, tableName)
+	if err != nil {
+		return nil, err
+	}
+	ts := &pb.TableSchema{
+		SchemaName:    set.SchemaName,
+		TableName:     set.TableName,
+		ColumnSchemas: set.ColumnSchemas,
+	}
+	ts.Indexes, err = getIndexes(ctx, conn, tableName)
+	if err != nil {
+		return nil, err
+	}
+	ts.UniqueConstraints, ts.CheckConstraints, err = getConstraints(ctx, conn, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+func getIndexes(ctx context.Context, conn Querier, tableName string) ([]*pb.Index, error) {
+	schema, tableName := splitTableName(tableName)
+	qualifiedTableName := fmt.Sprintf("%s.%s", schema, tableName)
+	indexes, err := queryIndexes(ctx, conn, "cl.oid = CAST($1 as regclass)", qualifiedTableName)
+	if err != nil {
+		return nil, fmt.Errorf("getIndexes failed: %w, table:%s, schema:%s", err, tableName, schema)
+	}
+	if list, ok := indexes[tableName]; ok {
+		return list, nil
+	}
+	return []*pb.Index{}, nil
+}
+
+// queryIndexes returns the indexes of the tables that match the condition of queryMetadata by table name.
+// The key columns of an index are listed in order, by name or by their expression.
+func queryIndexes(ctx context.Context, conn Querier, condition string, arg any) (map[string][]*pb.Index, error) {
+	query := `
+SELECT cl.relname, ic.relname,
+	ARRAY(
+		SELECT pg_get_indexdef(i.indexrelid, k.n, true)
+		FROM generate_series(1, i.indnkeyatts::int) AS k(n)
+		ORDER BY k.n
+	),
+	i.indisunique, i.indisprimary,
+	COALESCE(pg_get_expr(i.indpred, i.indrelid, true), ''),
+	am.amname::text
+FROM pg_index i
+JOIN pg_class ic ON ic.oid = i.indexrelid
+JOIN pg_am am ON am.oid = ic.relam
+JOIN pg_class cl ON cl.oid = i.indrelid
+JOIN pg_namespace ns ON ns.oid = cl.relnamespace
+WHERE ` + condition + `
+ORDER BY cl.relname, ic.relname;
+`
+	rows, err := conn.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	indexes := map[string][]*pb.Index{}
+	for rows.Next() {
+		var tableName string
+		index := new(pb.Index)
+		if err := rows.Scan(&tableName, &index.Name, &index.Columns, &index.IsUnique, &index.IsPrimary, &index.Predicate, &index.Method); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		// pg_get_indexdef quotes column names if needed
+		for i, each := range index.Columns {
+			index.Columns[i] = unquoteIdentifier(each)
+		}
+		indexes[tableName] = append(indexes[tableName], index)
+	}
+	return indexes, rows.Err()
+}
+
+// getConstraints returns the unique and check constraints of a table.
+// Primary keys, which are unique too, are not included.
+func getConstraints(ctx context.Context, conn Querier, tableName string) (unique []*pb.UniqueConstraint, check []*pb.CheckConstraint, err error) {
+	schema, tableName := splitTableName(tableName)
+	qualifiedTableName := fmt.Sprintf("%s.%s", schema, tableName)
+	constraints, err := queryConstraints(ctx, conn, "cl.oid = CAST($1 as regclass)", qualifiedTableName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getConstraints failed: %w, table:%s, schema:%s", err, tableName, schema)
+	}
+	unique, check = []*pb.UniqueConstraint{}, []*pb.CheckConstraint{}
+	if ts, ok := constraints[tableName]; ok {
+		unique, check = ts.UniqueConstraints, ts.CheckConstraints
+	}
+	return unique, check, nil
+}
+
+// queryConstraints returns the unique and check constraints of the tables that match the condition of queryMetadata by table name.
+// Only the constraints of the returned table schemas are set.
+func queryConstraints(ctx context.Context, conn Querier, condition string, arg any) (map[string]*pb.TableSchema, error) {
+	query := `
+SELECT cl.relname, c.conname, c.contype::text,
+	ARRAY(
+		SELECT a.attname
+		FROM unnest(c.conkey) WITH ORDINALITY AS k(attnum, n)
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+		ORDER BY k.n
+	),
+	COALESCE(pg_get_expr(c.conbin, c.conrelid, true), '')
+FROM pg_constraint c
+JOIN pg_class cl ON cl.oid = c.conrelid
+JOIN pg_namespace ns ON ns.oid = cl.relnamespace
+WHERE ` + condition + `
+  AND c.contype IN ('u', 'c')
+ORDER BY cl.relname, c.conname;
+`
+	rows, err := conn.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	constraints := map[string]*pb.TableSchema{}
+	for rows.Next() {
+		var tableName, name, contype, expression string
+		var columns []string
+		if err := rows.Scan(&tableName, &name, &contype, &columns, &expression); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		ts, ok := constraints[tableName]
+		if !ok {
+			ts = &pb.TableSchema{TableName: tableName}
+			constraints[tableName] = ts
+		}
+		if contype == "u" {
+			ts.UniqueConstraints = append(ts.UniqueConstraints, &pb.UniqueConstraint{Name: name, Columns: columns})
+		} else {
+			ts.CheckConstraints = append(ts.CheckConstraints, &pb.CheckConstraint{Name: name, Expression: expression})
+		}
+	}
+	return constraints, rows.Err()
+}
+
+// FetchSchemaMetadata returns the table schemas of all tables of a schema by their qualified name.
+// Unlike fetching the metadata of each table, it queries the catalog only a few times, regardless of the number of tables.
+// The metadata of each table is cached by its qualified name, as used by ExtractSubset and LoadBundle,
+// and is used by all functions given a metadata cache key that is not cached itself.
+// Unlike metadata cached on first use, it does not expire; call it again after changing the tables.
+func FetchSchemaMetadata(ctx context.Context, conn Querier, schema string) (map[string]*pb.TableSchema, error) {
+	condition := "ns.nspname = $1 AND cl.relkind IN ('r', 'p')"
+	sets, err := queryMetadata(ctx, conn, condition, schema)
+	if err != nil {
+		return nil, fmt.Errorf("FetchSchemaMetadata failed: %w, schema:%s", err, schema)
+	}
+	indexes, err := queryIndexes(ctx, conn, condition, schema)
+	if err != nil {
+		return nil, fmt.Errorf("FetchSchemaMetadata failed: %w, schema:%s", err, schema)
+	}
+	constraints, err := queryConstraints(ctx, conn, condition, schema)
+	if err != nil {
+		return nil, fmt.Errorf("FetchSchemaMetadata failed: %w, schema:%s", err, schema)
+	}
+	schemas := map[string]*pb.TableSchema{}
+	for tableName, set := range sets {
+		name := qualifiedName(schema, tableName)
+		metaCache.Set(name, set, cache.NoExpiration)
+		ts := &pb.TableSchema{
+			SchemaName:        schema,
+			TableName:         tableName,
+			ColumnSchemas:     set.ColumnSchemas,
+			Indexes:           indexes[tableName],
+			UniqueConstraints: []*pb.UniqueConstraint{},
+			CheckConstraints:  []*pb.CheckConstraint{},
+		}
+		if ts.Indexes == nil {
+			ts.Indexes = []*pb.Index{}
+		}
+		if each, ok := constraints[tableName]; ok {
+			ts.UniqueConstraints, ts.CheckConstraints = each.UniqueConstraints, each.CheckConstraints
+		}
+		schemas[name] = ts
+	}
+	return schemas, nil
+}
+
+// WarmCache caches the metadata of all tables of the given schemas, or of all schemas if none are given.
+// Services can call it at startup to avoid querying the metadata of each table on first use.
+// The metadata is cached by qualified table name without expiration, see FetchSchemaMetadata.
+func WarmCache(ctx context.Context, conn Querier, schemas ...string) error {
+	if len(schemas) == 0 {
+		names, err := getSchemaNames(ctx, conn)
+		if err != nil {
+			return err
+		}
+		schemas = names
+	}
+	for _, each := range schemas {
+		if _, err := FetchSchemaMetadata(ctx, conn, each); err != nil {
+			return err
+		}
+	}
+	return nil
+}
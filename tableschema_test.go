@@ -0,0 +1,161 @@
+package anyrow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+func TestGetIndexes(t *testing.T) {
+	conn := &mockQuerier{results: [][][]any{{
+		{"lines", "lines_pkey", []string{"order_id", "nr"}, true, true, "", "btree"},
+		{"lines", "lines_product_idx", []string{"lower(product)"}, false, false, "product IS NOT NULL", "btree"},
+		{"lines", "lines_order_idx", []string{`"Order"`, `"say ""hi"""`, `("Order" || "Order")`}, false, false, "", "btree"},
+	}}}
+	indexes, err := getIndexes(context.Background(), conn, "lines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.args, []any{"public.lines"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(indexes), 3; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := indexes[1].Predicate, "product IS NOT NULL"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	ts := &pb.TableSchema{Indexes: indexes}
+	if !ts.IsIndexed("order_id") {
+		t.Error("order_id indexed expected")
+	}
+	if ts.IsIndexed("nr") || ts.IsIndexed("lower(product)") {
+		t.Error("not indexed expected")
+	}
+	if !ts.IsIndexed("Order") {
+		t.Error("Order indexed expected")
+	}
+	if got, want := indexes[2].Columns, []string{"Order", `say "hi"`, `("Order" || "Order")`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestGetConstraints(t *testing.T) {
+	conn := &mockQuerier{results: [][][]any{{
+		{"lines", "lines_nr_check", "c", []string{"nr"}, "nr > 0"},
+		{"lines", "lines_product_key", "u", []string{"order_id", "product"}, ""},
+	}}}
+	unique, check, err := getConstraints(context.Background(), conn, "lines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(unique), 1; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := unique[0].Columns, []string{"order_id", "product"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := check[0].Expression, "nr > 0"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+// columnRow returns a row as queried by queryMetadata.
+func columnRow(tableName, name, typeName string, isPrimary bool, position int32) []any {
+	return []any{"public", tableName, name, typeName, "NO", isPrimary, typeName, uint32(20), "", []string{}, uint32(0), uint32(0),
+		position, "", int32(0), int32(64), int32(0), false, "", false, "", ""}
+}
+
+func TestFetchSchemaMetadata(t *testing.T) {
+	metaCache.Delete("public.shelves")
+	conn := &mockQuerier{results: [][][]any{
+		{
+			columnRow("books", "id", "bigint", true, 1),
+			columnRow("books", "shelf_id", "bigint", false, 2),
+			columnRow("shelves", "id", "bigint", true, 1),
+		},
+		{{"books", "books_pkey", []string{"id"}, true, true, "", "btree"}},
+		{{"shelves", "shelves_id_check", "c", []string{"id"}, "id > 0"}},
+	}}
+	schemas, err := FetchSchemaMetadata(context.Background(), conn, "public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(conn.sqls), 3; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	books := schemas["public.books"]
+	if got, want := len(books.ColumnSchemas), 2; got != want {
+		t.Fatalf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if !books.IsIndexed("id") || len(books.CheckConstraints) != 0 {
+		t.Errorf("unexpected books schema %v", books)
+	}
+	shelves := schemas["public.shelves"]
+	if got, want := len(shelves.Indexes), 0; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := shelves.CheckConstraints[0].Expression, "id > 0"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	cached, ok := metaCache.Get("public.shelves")
+	if !ok {
+		t.Fatal("cached metadata expected")
+	}
+	if got, want := cached.(*pb.RowSet).ColumnSchemas[0].IsPrimarykey, true; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if _, expiration, _ := metaCache.GetWithExpiration("public.shelves"); !expiration.IsZero() {
+		t.Errorf("no expiration expected, got %v", expiration)
+	}
+	// a key that is not cached falls back to the qualified table name
+	metaCache.Delete("testshelveskey")
+	set, err := cachedMetadata(context.Background(), conn, "testshelveskey", "shelves")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := set, cached; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(conn.sqls), 3; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestQueryMetadataComposites(t *testing.T) {
+	home := columnRow("people", "home", "USER-DEFINED", false, 1)
+	home[7], home[11] = uint32(16400), uint32(16400)
+	homes := columnRow("people", "homes", "ARRAY", false, 2)
+	homes[7], homes[10], homes[11] = uint32(16399), uint32(16400), uint32(16400)
+	conn := &mockQuerier{results: [][][]any{
+		{home, homes},
+		{
+			{uint32(16390), "lat", "double precision", true, "float8", uint32(701), uint32(0), uint32(0)},
+			{uint32(16400), "street", "text", true, "text", uint32(25), uint32(0), uint32(0)},
+			{uint32(16400), "location", "geo", true, "geo", uint32(16390), uint32(0), uint32(16390)},
+		},
+	}}
+	sets, err := queryMetadata(context.Background(), conn, "cl.oid = CAST($1 as regclass)", "public.people")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the attributes of all composite types are queried at once
+	if got, want := len(conn.sqls), 2; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.args, []any{[]uint32{16400}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	columns := sets["people"].ColumnSchemas
+	if got, want := columns[0].AttributeSchemas[1].AttributeSchemas[0].Name, "lat"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := columns[1].ElementTypeOid, uint32(16400); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := len(columns[1].AttributeSchemas), 2; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
@@ -0,0 +1,373 @@
+package anyrow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/netip"
+	"time"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// valueConverter passes a value of a known data type to the collector.
+type valueConverter func(collector valueCollector, index int, value any) error
+
+// oidConverters holds the converters by the OID of the data type of a column.
+// Values of other data types are passed to the collector depending on their Go type.
+var oidConverters = map[uint32]valueConverter{
+	pgtype.Int2OID: storeInteger,
+	pgtype.Int4OID: storeInteger,
+	pgtype.Int8OID: storeInteger,
+	pgtype.DateOID: storeDateValue,
+	pgtype.InetOID: storeInet,
+}
+
+// moneyOID is the OID of the money data type, which is unknown to pgx.
+// Its values are selected as numeric because the text format depends on the locale of the database.
+const moneyOID = 790
+
+func storeInteger(collector valueCollector, index int, value any) error {
+	switch v := value.(type) {
+	case int64:
+		collector.storeInt64(index, v)
+	case int32:
+		collector.storeInt64(index, int64(v))
+	case int16:
+		collector.storeInt64(index, int64(v))
+	case float64:
+		fint, _ := math.Modf(v)
+		collector.storeInt64(index, int64(fint))
+	default:
+		return storeByType(collector, index, value)
+	}
+	return nil
+}
+
+// storeInet stores an address as text, without the netmask if it covers a single host like Postgres does.
+func storeInet(collector valueCollector, index int, value any) error {
+	if v, ok := value.(netip.Prefix); ok && v.Bits() == v.Addr().BitLen() {
+		collector.storeString(index, v.Addr().String())
+		return nil
+	}
+	return storeByType(collector, index, value)
+}
+
+// storeDateValue stores a date, which pgx returns as a time.Time at midnight UTC.
+func storeDateValue(collector valueCollector, index int, value any) error {
+	if v, ok := value.(time.Time); ok {
+		collector.storeDate(index, v)
+		return nil
+	}
+	return storeByType(collector, index, value)
+}
+
+// storeNumeric stores a numeric as a decimal without loss of precision.
+// NaN and infinite values, which have no decimal representation, are stored as strings.
+func storeNumeric(collector valueCollector, index int, value pgtype.Numeric) error {
+	if !value.Valid {
+		collector.storeNull(index)
+		return nil
+	}
+	text, err := value.Value()
+	if err != nil {
+		return fmt.Errorf("invalid numeric value: %w", err)
+	}
+	if value.NaN || value.InfinityModifier != pgtype.Finite {
+		collector.storeString(index, text.(string))
+		return nil
+	}
+	collector.storeDecimal(index, text.(string))
+	return nil
+}
+
+// arrayElementOID returns the OID of the element type if the OID is that of a known array type.
+func arrayElementOID(oid uint32) (uint32, bool) {
+	typ, ok := defaultTypeMap.TypeForOID(oid)
+	if !ok {
+		return 0, false
+	}
+	codec, ok := typ.Codec.(*pgtype.ArrayCodec)
+	if !ok {
+		return 0, false
+	}
+	return codec.ElementType.OID, true
+}
+
+// storeArrayValue stores the elements of an array converted like column values of the element type.
+func storeArrayValue(collector valueCollector, index int, elementOID uint32, values []any) error {
+	elements, err := arrayElements(elementOID, values)
+	if err != nil {
+		return err
+	}
+	collector.storeArray(index, elements)
+	return nil
+}
+
+// arrayElements returns the elements as they would be stored in a Record.
+// Nested slices, the rows of a multidimensional array, are converted recursively.
+func arrayElements(elementOID uint32, values []any) ([]any, error) {
+	elements := make([]any, len(values))
+	single := &objectCollector{set: &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{{Name: "element"}}}}
+	for i, each := range values {
+		switch v := each.(type) {
+		case nil:
+		case []any:
+			nested, err := arrayElements(elementOID, v)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = nested
+		default:
+			single.object = Record{}
+			if err := storeConverted(single, 0, elementOID, v); err != nil {
+				return nil, err
+			}
+			elements[i] = single.object["element"]
+		}
+	}
+	return elements, nil
+}
+
+// shapeArrays replaces the flat elements of multidimensional arrays, as returned by pgx, by nested slices.
+// The dimensions are read from the raw value if the array was returned in binary format.
+func shapeArrays(fields []pgconn.FieldDescription, raws [][]byte, all []any) {
+	for i, each := range all {
+		flat, ok := each.([]any)
+		if !ok || i >= len(fields) || i >= len(raws) || fields[i].Format != pgtype.BinaryFormatCode {
+			continue
+		}
+		if _, ok := arrayElementOID(fields[i].DataTypeOID); !ok {
+			continue
+		}
+		if dims := arrayDimensions(raws[i]); len(dims) > 1 {
+			all[i] = nestElements(flat, dims)
+		}
+	}
+}
+
+// arrayDimensions returns the length of each dimension from the header of an array in binary format.
+// The header has the number of dimensions, a null flag, the element OID and a length and lower bound per dimension.
+func arrayDimensions(raw []byte) []int {
+	if len(raw) < 12 {
+		return nil
+	}
+	ndims := int(binary.BigEndian.Uint32(raw))
+	if len(raw) < 12+8*ndims {
+		return nil
+	}
+	dims := make([]int, ndims)
+	for i := range dims {
+		dims[i] = int(binary.BigEndian.Uint32(raw[12+8*i:]))
+	}
+	return dims
+}
+
+// nestElements returns the flat elements as nested slices with the given dimensions.
+func nestElements(flat []any, dims []int) []any {
+	if len(dims) <= 1 {
+		return flat
+	}
+	size := len(flat) / max(dims[0], 1)
+	nested := make([]any, dims[0])
+	for i := range nested {
+		nested[i] = nestElements(flat[i*size:(i+1)*size], dims[1:])
+	}
+	return nested
+}
+
+// rangeElementOID returns the OID of the element type if the OID is that of a known range type.
+func rangeElementOID(oid uint32) (uint32, bool) {
+	typ, ok := defaultTypeMap.TypeForOID(oid)
+	if !ok {
+		return 0, false
+	}
+	codec, ok := typ.Codec.(*pgtype.RangeCodec)
+	if !ok {
+		return 0, false
+	}
+	return codec.ElementType.OID, true
+}
+
+// storeRangeValue stores the bounds of a range converted like column values of the element type.
+func storeRangeValue(collector valueCollector, index int, elementOID uint32, value pgtype.Range[any]) error {
+	if value.LowerType == pgtype.Empty {
+		collector.storeRange(index, nil, nil, "", true)
+		return nil
+	}
+	bounds := []byte("()")
+	if value.LowerType == pgtype.Unbounded {
+		value.Lower = nil
+	} else if value.LowerType == pgtype.Inclusive {
+		bounds[0] = '['
+	}
+	if value.UpperType == pgtype.Unbounded {
+		value.Upper = nil
+	} else if value.UpperType == pgtype.Inclusive {
+		bounds[1] = ']'
+	}
+	elements, err := arrayElements(elementOID, []any{value.Lower, value.Upper})
+	if err != nil {
+		return err
+	}
+	collector.storeRange(index, elements[0], elements[1], string(bounds), false)
+	return nil
+}
+
+// multirangeRangeOID returns the OID of the range type if the OID is that of a known multirange type.
+func multirangeRangeOID(oid uint32) (uint32, bool) {
+	typ, ok := defaultTypeMap.TypeForOID(oid)
+	if !ok {
+		return 0, false
+	}
+	codec, ok := typ.Codec.(*pgtype.MultirangeCodec)
+	if !ok {
+		return 0, false
+	}
+	return codec.ElementType.OID, true
+}
+
+// storeMultirangeValue stores the ranges of a multirange like the elements of an array of its range type.
+func storeMultirangeValue(collector valueCollector, index int, rangeOID uint32, value pgtype.Multirange[pgtype.Range[any]]) error {
+	values := make([]any, len(value))
+	for i, each := range value {
+		values[i] = each
+	}
+	return storeArrayValue(collector, index, rangeOID, values)
+}
+
+// geometricCoordinates returns the coordinates of a value of a geometric type.
+// A point is [x,y], a line segment or box is [[x1,y1],[x2,y2]] and a path or polygon is a list of points.
+// A line is [A,B,C] of its equation Ax + By + C = 0 and a circle is [x,y,r] of its center and radius.
+func geometricCoordinates(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case pgtype.Point:
+		return pointCoordinates(v.P), true
+	case pgtype.Lseg:
+		return []any{pointCoordinates(v.P[0]), pointCoordinates(v.P[1])}, true
+	case pgtype.Box:
+		return []any{pointCoordinates(v.P[0]), pointCoordinates(v.P[1])}, true
+	case pgtype.Path:
+		return pointsCoordinates(v.P), true
+	case pgtype.Polygon:
+		return pointsCoordinates(v.P), true
+	case pgtype.Line:
+		return []any{v.A, v.B, v.C}, true
+	case pgtype.Circle:
+		return []any{v.P.X, v.P.Y, v.R}, true
+	}
+	return nil, false
+}
+
+func pointCoordinates(p pgtype.Vec2) []any {
+	return []any{p.X, p.Y}
+}
+
+func pointsCoordinates(points []pgtype.Vec2) []any {
+	list := make([]any, len(points))
+	for i, each := range points {
+		list[i] = pointCoordinates(each)
+	}
+	return list
+}
+
+// storeCompositeValue stores the attribute values of a composite value.
+// A value of a composite type is returned as text; a row-valued expression as the values of its attributes.
+func storeCompositeValue(collector valueCollector, index int, schema *pb.ColumnSchema, value any) error {
+	schemas := schema.AttributeSchemas
+	var values []any
+	switch v := value.(type) {
+	case string:
+		fields, err := compositeFields(schemas, v)
+		if err != nil {
+			return fmt.Errorf("invalid composite value of column %q: %w", schema.Name, err)
+		}
+		values = fields
+	case []any:
+		values = v
+	default:
+		return storeByType(collector, index, value)
+	}
+	if len(schemas) == 0 {
+		// attributes of a row-valued expression are named like Postgres does
+		for i := range values {
+			schemas = append(schemas, &pb.ColumnSchema{Name: fmt.Sprintf("f%d", i+1), TypeName: "unknown", IsNullable: true})
+		}
+	}
+	if len(values) != len(schemas) {
+		return fmt.Errorf("composite value of column %q has %d attributes, expected %d", schema.Name, len(values), len(schemas))
+	}
+	set := &pb.RowSet{ColumnSchemas: schemas}
+	record := &objectCollector{set: set}
+	record.nextRow(len(values))
+	if err := storeValues(set, values, record); err != nil {
+		return err
+	}
+	collector.storeComposite(index, schemas, record.object)
+	return nil
+}
+
+// storeCompositeArrayValue stores the elements of an array of a composite type, which is returned as text.
+// The elements of a multidimensional array are stored as a flat list.
+func storeCompositeArrayValue(collector valueCollector, index int, schema *pb.ColumnSchema, value any) error {
+	text, ok := value.(string)
+	if !ok {
+		return storeByType(collector, index, value)
+	}
+	typ, _ := defaultTypeMap.TypeForOID(pgtype.TextArrayOID)
+	decoded, err := typ.Codec.DecodeValue(defaultTypeMap, pgtype.TextArrayOID, pgtype.TextFormatCode, []byte(text))
+	if err != nil {
+		return fmt.Errorf("invalid array value of column %q: %w", schema.Name, err)
+	}
+	values, _ := decoded.([]any)
+	element := &pb.ColumnSchema{Name: schema.Name, TypeOid: schema.ElementTypeOid, AttributeSchemas: schema.AttributeSchemas}
+	single := &objectCollector{set: &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{element}}}
+	elements := make([]any, len(values))
+	for i, each := range values {
+		if each == nil {
+			continue
+		}
+		single.nextRow(1)
+		if err := storeCompositeValue(single, 0, element, each); err != nil {
+			return err
+		}
+		elements[i] = single.object[schema.Name]
+	}
+	collector.storeArray(index, elements)
+	return nil
+}
+
+// compositeFields returns the attribute values of a composite value in text format.
+// Each value is decoded using the codec of the data type of its attribute, if known to pgx.
+func compositeFields(schemas []*pb.ColumnSchema, text string) ([]any, error) {
+	scanner := pgtype.NewCompositeTextScanner(defaultTypeMap, []byte(text))
+	values := []any{}
+	for scanner.Next() {
+		field := scanner.Bytes()
+		switch {
+		case field == nil:
+			values = append(values, nil)
+		case len(values) >= len(schemas):
+			values = append(values, string(field))
+		default:
+			values = append(values, decodeAttributeText(schemas[len(values)], field))
+		}
+	}
+	return values, scanner.Err()
+}
+
+// decodeAttributeText returns the text decoded using the codec of the data type of the attribute.
+// The text is returned as is if the data type is unknown or the value cannot be decoded.
+func decodeAttributeText(schema *pb.ColumnSchema, text []byte) any {
+	typ, ok := defaultTypeMap.TypeForOID(schema.TypeOid)
+	if !ok || len(schema.AttributeSchemas) > 0 {
+		return string(text)
+	}
+	value, err := typ.Codec.DecodeValue(defaultTypeMap, schema.TypeOid, pgtype.TextFormatCode, text)
+	if err != nil {
+		return string(text)
+	}
+	return value
+}
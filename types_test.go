@@ -0,0 +1,567 @@
+package anyrow
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestStoreValuesByOID(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "count", TypeName: "integer", TypeOid: pgtype.Int4OID},
+		{Name: "ratio", TypeName: "double precision", TypeOid: pgtype.Float8OID},
+		{Name: "size", TypeName: "shoesize", TypeOid: pgtype.Int4OID},
+		{Name: "active", TypeName: "yesno", TypeOid: pgtype.BoolOID},
+		{Name: "mood", TypeName: "USER-DEFINED", TypeOid: 99998},
+	}}
+	// size and active are domains, which pgx decodes by their base type; mood is an enum unknown to pgx
+	collector := &objectCollector{set: set}
+	collector.nextRow(len(set.ColumnSchemas))
+	if err := storeValues(set, []any{float64(3), float64(0.5), int32(42), true, "happy"}, collector); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]any{
+		"count":  int64(3),
+		"ratio":  float64(0.5),
+		"size":   int64(42),
+		"active": true,
+		"mood":   "happy",
+	} {
+		if got := collector.object[key]; got != want {
+			t.Errorf("%s: got [%v:%T] want [%v:%T]", key, got, got, want, want)
+		}
+	}
+}
+
+func TestStoreValuesLossless(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "amount", TypeName: "numeric", TypeOid: pgtype.NumericOID},
+		{Name: "distance", TypeName: "double precision", TypeOid: pgtype.Float8OID},
+		{Name: "nan", TypeName: "numeric", TypeOid: pgtype.NumericOID},
+	}}
+	amount := pgtype.Numeric{}
+	if err := amount.Scan("90662617867046219066261786704621.0100"); err != nil {
+		t.Fatal(err)
+	}
+	distance := 1.25e300
+	collector := &rowsetCollector{set: set}
+	collector.nextRow(3)
+	if err := storeValues(set, []any{amount, distance, pgtype.Numeric{NaN: true, Valid: true}}, collector); err != nil {
+		t.Fatal(err)
+	}
+	// survive a protobuf round trip
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back := new(pb.RowSet)
+	if err := proto.Unmarshal(data, back); err != nil {
+		t.Fatal(err)
+	}
+	mp := back.RowMap(0)
+	if got, want := mp["amount"], json.Number("90662617867046219066261786704621.0100"); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := mp["distance"], distance; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := mp["nan"], "NaN"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := back.JSONString(), `[{"amount":90662617867046219066261786704621.0100
+,"distance":1.25e+300
+,"nan":"NaN"
+}]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesTemporal(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "ts", TypeName: "timestamp with time zone", TypeOid: pgtype.TimestamptzOID},
+		{Name: "day", TypeName: "date", TypeOid: pgtype.DateOID},
+		{Name: "at", TypeName: "time without time zone", TypeOid: pgtype.TimeOID},
+		{Name: "span", TypeName: "interval", TypeOid: pgtype.IntervalOID},
+		{Name: "forever", TypeName: "date", TypeOid: pgtype.DateOID},
+	}}
+	amsterdam := time.FixedZone("CET", 3600)
+	ts := time.Date(2024, 2, 29, 13, 14, 15, 123456000, amsterdam)
+	day := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	at := pgtype.Time{Microseconds: (13*3600+14*60+15)*1_000_000 + 500_000, Valid: true}
+	span := pgtype.Interval{Months: 14, Days: 3, Microseconds: (4*3600+5*60+6)*1_000_000 + 500_000, Valid: true}
+	values := []any{ts, day, at, span, pgtype.Infinity}
+
+	objects := &objectCollector{set: set}
+	objects.nextRow(len(values))
+	if err := storeValues(set, values, objects); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := objects.object["ts"], ts.UTC(); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := objects.object["at"].(*pb.TimeOfDay).Format(), "13:14:15.5"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := objects.object["span"].(*pb.Interval).Format(), "P1Y2M3DT4H5M6.5S"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	mp := set.RowMap(0)
+	if got, want := mp["ts"], ts.UTC(); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := mp["day"], day; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := set.JSONString(), `[{"ts":"2024-02-29T12:14:15.123456Z"
+,"day":"2024-02-29"
+,"at":"13:14:15.5"
+,"span":"P1Y2M3DT4H5M6.5S"
+,"forever":"infinity"
+}]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesArrays(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "ints", TypeName: "ARRAY", TypeOid: pgtype.Int4ArrayOID},
+		{Name: "ids", TypeName: "ARRAY", TypeOid: pgtype.UUIDArrayOID},
+		{Name: "amounts", TypeName: "ARRAY", TypeOid: pgtype.NumericArrayOID},
+		{Name: "matrix", TypeName: "ARRAY", TypeOid: pgtype.Int4ArrayOID},
+	}}
+	matrix, err := defaultTypeMap.Encode(pgtype.Int4ArrayOID, pgtype.BinaryFormatCode, [][]int32{{1, 2, 3}, {4, 5, 6}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, _ := defaultTypeMap.TypeForOID(pgtype.Int4ArrayOID)
+	flat, err := typ.Codec.DecodeValue(defaultTypeMap, pgtype.Int4ArrayOID, pgtype.BinaryFormatCode, matrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []pgconn.FieldDescription{
+		{DataTypeOID: pgtype.Int4ArrayOID, Format: pgtype.BinaryFormatCode},
+		{DataTypeOID: pgtype.UUIDArrayOID, Format: pgtype.BinaryFormatCode},
+		{DataTypeOID: pgtype.NumericArrayOID, Format: pgtype.BinaryFormatCode},
+		{DataTypeOID: pgtype.Int4ArrayOID, Format: pgtype.BinaryFormatCode},
+	}
+	amount := pgtype.Numeric{}
+	amount.Scan("12.50")
+	values := []any{
+		[]any{int32(1), nil, int32(3)},
+		[]any{[16]uint8{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}},
+		[]any{amount},
+		flat,
+	}
+	shapeArrays(fields, [][]byte{nil, nil, nil, matrix}, values)
+
+	objects := &objectCollector{set: set}
+	objects.nextRow(len(values))
+	if err := storeValues(set, values, objects); err != nil {
+		t.Fatal(err)
+	}
+	want := Record{
+		"ints":    []any{int64(1), nil, int64(3)},
+		"ids":     []any{"12345678-9abc-def0-1234-56789abcdef0"},
+		"amounts": []any{json.Number("12.50")},
+		"matrix":  []any{[]any{int64(1), int64(2), int64(3)}, []any{int64(4), int64(5), int64(6)}},
+	}
+	if got := objects.object; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := set.RowMap(0)["matrix"], want["matrix"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := set.JSONString(), `[{"ints":[1
+,null
+,3
+]
+,"ids":["12345678-9abc-def0-1234-56789abcdef0"
+]
+,"amounts":[12.50
+]
+,"matrix":[[1
+,2
+,3
+]
+,[4
+,5
+,6
+]
+]
+}]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesCompositeArray(t *testing.T) {
+	address := []*pb.ColumnSchema{
+		{Name: "street", TypeName: "text", TypeOid: pgtype.TextOID},
+		{Name: "number", TypeName: "integer", TypeOid: pgtype.Int4OID},
+	}
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "homes", TypeName: "ARRAY", UdtName: "_address", TypeOid: 16399, ElementTypeOid: 16400, AttributeSchemas: address},
+	}}
+	values := []any{`{"(\"Main St\",12)",NULL}`}
+
+	objects := &objectCollector{set: set}
+	objects.nextRow(len(values))
+	if err := storeValues(set, values, objects); err != nil {
+		t.Fatal(err)
+	}
+	want := Record{"homes": []any{Record{"street": "Main St", "number": int64(12)}, nil}}
+	if got := objects.object; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := set.JSONString(), `[{"homes":[{"street":"Main St"
+,"number":12
+}
+,null
+]
+}]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := parameterValue(set.ColumnSchemas[0], rows.row.Columns[0]), `{"(\"Main St\",\"12\")",NULL}`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesRangeArrays(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "spans", TypeName: "ARRAY", TypeOid: pgtype.Int4rangeArrayOID},
+		{Name: "free", TypeName: "int4multirange", TypeOid: pgtype.Int4multirangeOID},
+	}}
+	span := pgtype.Range[any]{Lower: int32(1), Upper: int32(3), LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive, Valid: true}
+	values := []any{
+		[]any{span, nil},
+		pgtype.Multirange[pgtype.Range[any]]{span, {LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}},
+	}
+
+	objects := &objectCollector{set: set}
+	objects.nextRow(len(values))
+	if err := storeValues(set, values, objects); err != nil {
+		t.Fatal(err)
+	}
+	bounds := map[string]any{"lower": int64(1), "upper": int64(3), "bounds": "[)"}
+	want := Record{
+		"spans": []any{bounds, nil},
+		"free":  []any{bounds, map[string]any{"empty": true}},
+	}
+	if got := objects.object; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rows.row.Columns[0].GetArrayValue().GetElements()[0].GetRangeValue().GetBounds(), "[)"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows.row.Columns[1].GetArrayValue().GetElements()[1].GetRangeValue().GetEmpty(), true; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := parameterValue(set.ColumnSchemas[0], rows.row.Columns[0]), `{"[\"1\",\"3\")",NULL}`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := parameterValue(set.ColumnSchemas[1], rows.row.Columns[1]), `{["1","3"),empty}`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesDates(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "days", TypeName: "ARRAY", TypeOid: pgtype.DateArrayOID},
+		{Name: "period", TypeName: "daterange", TypeOid: pgtype.DaterangeOID},
+	}}
+	day := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	values := []any{
+		[]any{day, nil},
+		pgtype.Range[any]{Lower: day, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rows.row.Columns[0].GetArrayValue().GetElements()[0].GetDateValue().Format(), "2024-02-29"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := rows.row.Columns[1].GetRangeValue().GetLower().GetDateValue().Format(), "2024-02-29"; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := parameterValue(set.ColumnSchemas[0], rows.row.Columns[0]), `{"2024-02-29",NULL}`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesNull(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "str", TypeName: "text", TypeOid: pgtype.TextOID},
+		{Name: "num", TypeName: "integer", TypeOid: pgtype.Int4OID},
+	}}
+	values := []any{nil, int32(1)}
+
+	objects := &objectCollector{set: set}
+	objects.nextRow(len(values))
+	if err := storeValues(set, values, objects); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := objects.object["str"]; !ok || v != nil {
+		t.Errorf("got [%v:%v] want [nil:true]", v, ok)
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rows.row.Columns[0].GetNullValue(), structpb.NullValue_NULL_VALUE; rows.row.Columns[0].GetJsonValue() == nil || got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if v, ok := set.RowMap(0)["str"]; !ok || v != nil {
+		t.Errorf("got [%v:%v] want [nil:true]", v, ok)
+	}
+	if got, want := set.JSONString(), `[{"str":null
+,"num":1
+}]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesInvalidNumeric(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "amount", TypeName: "numeric", TypeOid: pgtype.NumericOID},
+	}}
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(1)
+	if err := storeValues(set, []any{pgtype.Numeric{}}, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rows.row.Columns[0].GetJsonValue(), any(&pb.ColumnValue_NullValue{}); reflect.TypeOf(got) != reflect.TypeOf(want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesBytes(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "data", TypeName: "bytea", TypeOid: pgtype.ByteaOID},
+	}}
+	values := []any{[]byte("hello")}
+
+	objects := &objectCollector{set: set}
+	objects.nextRow(len(values))
+	if err := storeValues(set, values, objects); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := objects.object["data"], []byte("hello"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := set.RowMap(0)["data"], []byte("hello"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := set.JSONString(), `[{"data":"aGVsbG8="
+}]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesSpecialTypes(t *testing.T) {
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "host", TypeName: "inet", TypeOid: pgtype.InetOID},
+		{Name: "net", TypeName: "cidr", TypeOid: pgtype.CIDROID},
+		{Name: "mac", TypeName: "macaddr", TypeOid: pgtype.MacaddrOID},
+		{Name: "span", TypeName: "int4range", TypeOid: pgtype.Int4rangeOID},
+		{Name: "since", TypeName: "daterange", TypeOid: pgtype.DaterangeOID},
+		{Name: "none", TypeName: "int4range", TypeOid: pgtype.Int4rangeOID},
+		{Name: "location", TypeName: "point", TypeOid: pgtype.PointOID},
+		{Name: "area", TypeName: "box", TypeOid: pgtype.BoxOID},
+		{Name: "shape", TypeName: "polygon", TypeOid: pgtype.PolygonOID},
+	}}
+	mac, _ := net.ParseMAC("08:00:2b:01:02:03")
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	values := []any{
+		netip.MustParsePrefix("192.168.0.1/32"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		mac,
+		pgtype.Range[any]{Lower: int32(1), Upper: int32(10), LowerType: pgtype.Inclusive, UpperType: pgtype.Exclusive, Valid: true},
+		pgtype.Range[any]{Lower: day, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+		pgtype.Range[any]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		pgtype.Point{P: pgtype.Vec2{X: 1, Y: 2}, Valid: true},
+		pgtype.Box{P: [2]pgtype.Vec2{{X: 3, Y: 4}, {X: 1, Y: 2}}, Valid: true},
+		pgtype.Polygon{P: []pgtype.Vec2{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}}, Valid: true},
+	}
+
+	objects := &objectCollector{set: set}
+	objects.nextRow(len(values))
+	if err := storeValues(set, values, objects); err != nil {
+		t.Fatal(err)
+	}
+	want := Record{
+		"host":     "192.168.0.1",
+		"net":      "10.0.0.0/8",
+		"mac":      "08:00:2b:01:02:03",
+		"span":     map[string]any{"lower": int64(1), "upper": int64(10), "bounds": "[)"},
+		"since":    map[string]any{"lower": day, "upper": nil, "bounds": "[)"},
+		"none":     map[string]any{"empty": true},
+		"location": []any{1.0, 2.0},
+		"area":     []any{[]any{3.0, 4.0}, []any{1.0, 2.0}},
+		"shape":    []any{[]any{0.0, 0.0}, []any{0.0, 1.0}, []any{1.0, 0.0}},
+	}
+	for k, v := range want {
+		if got, want := objects.object[k], v; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got [%v:%T] want [%v:%T]", k, got, got, want, want)
+		}
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := set.RowMap(0)["span"], want["span"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := set.RowJSONString(0), `{"host":"192.168.0.1"
+,"net":"10.0.0.0/8"
+,"mac":"08:00:2b:01:02:03"
+,"span":{"lower":1
+,"upper":10
+,"bounds":"[)"
+}
+,"since":{"lower":"2024-01-02"
+,"upper":null
+,"bounds":"[)"
+}
+,"none":{"empty":true}
+,"location":[1
+,2
+]
+,"area":[[3
+,4
+]
+,[1
+,2
+]
+]
+,"shape":[[0
+,0
+]
+,[0
+,1
+]
+,[1
+,0
+]
+]
+}`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestStoreValuesComposite(t *testing.T) {
+	point := []*pb.ColumnSchema{
+		{Name: "lat", TypeName: "double precision", TypeOid: pgtype.Float8OID},
+		{Name: "lon", TypeName: "double precision", TypeOid: pgtype.Float8OID},
+	}
+	address := []*pb.ColumnSchema{
+		{Name: "street", TypeName: "text", TypeOid: pgtype.TextOID},
+		{Name: "number", TypeName: "integer", TypeOid: pgtype.Int4OID},
+		{Name: "city", TypeName: "text", TypeOid: pgtype.TextOID},
+		{Name: "location", TypeName: "geo", TypeOid: 16390, AttributeSchemas: point},
+	}
+	set := &pb.RowSet{ColumnSchemas: []*pb.ColumnSchema{
+		{Name: "home", TypeName: "USER-DEFINED", UdtName: "address", TypeOid: 16400, AttributeSchemas: address},
+		{Name: "row", TypeName: "record", TypeOid: pgtype.RecordOID},
+	}}
+	values := []any{`("Main St, ""A""",12,,"(52.1,4.3)")`, []any{int32(1), "a"}}
+
+	objects := &objectCollector{set: set}
+	objects.nextRow(len(values))
+	if err := storeValues(set, values, objects); err != nil {
+		t.Fatal(err)
+	}
+	want := Record{
+		"home": Record{"street": `Main St, "A"`, "number": int64(12), "city": nil, "location": Record{"lat": 52.1, "lon": 4.3}},
+		"row":  Record{"f1": int64(1), "f2": "a"},
+	}
+	if got := objects.object; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	rows := &rowsetCollector{set: set}
+	rows.nextRow(len(values))
+	if err := storeValues(set, values, rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := set.RowMap(0)["home"].(map[string]any)["location"], map[string]any{"lat": 52.1, "lon": 4.3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := set.JSONString(), `[{"home":{"street":"Main St, \"A\""
+,"number":12
+,"city":null
+,"location":{"lat":52.1
+,"lon":4.3
+}
+}
+,"row":{"f1":1
+,"f2":"a"
+}
+}]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+
+	if err := storeValues(set, []any{"not a composite", nil}, objects); err == nil {
+		t.Error("error expected")
+	}
+}
+
+func TestJSONStringNotANumber(t *testing.T) {
+	set := &pb.RowSet{
+		ColumnSchemas: []*pb.ColumnSchema{{Name: "r"}, {Name: "d"}},
+		Rows: []*pb.Row{{Columns: []*pb.ColumnValue{
+			{JsonValue: &pb.ColumnValue_NumberFloatValue{NumberFloatValue: float32(math.NaN())}},
+			{JsonValue: &pb.ColumnValue_NumberDoubleValue{NumberDoubleValue: math.Inf(-1)}},
+		}}},
+	}
+	if got, want := set.JSONString(), `[{"r":"NaN"
+,"d":"-Inf"
+}]`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
@@ -0,0 +1,106 @@
+package anyrow
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/emicklei/anyrow/pb"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// UpdateRecord updates the columns of the rows identified by the key values with the values of a Record.
+// Updating a primary key column is refused, as is selecting rows by columns that are not part of the primary key
+// unless the WriteNonKeyColumns option is given.
+// Keys of the Record that are not columns of the table are ignored, unless the WriteStrict option is given.
+// With the WriteImages option, the rows before and after the update are returned too;
+// the before image is read, with its rows locked, by the same statement that updates them.
+func UpdateRecord(ctx context.Context, conn Querier, metadataCacheKey, tableName string, pkv PrimaryKeysAndValues, record Record, options ...writeOption) (WriteResult, error) {
+	result := WriteResult{}
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return result, err
+	}
+	cfg := newWriteConfig(options)
+	if err := cfg.checkKeyColumns(set, pkv); err != nil {
+		return result, err
+	}
+	if err := cfg.checkColumns(set, record); err != nil {
+		return result, err
+	}
+	if err := checkEnumValues(set, record); err != nil {
+		return result, err
+	}
+	filter := fetchFilter{
+		pkv: pkv,
+	}
+	// key parameters come first
+	args := pkv.parameterValues()
+	keyCount := len(args)
+	qb := new(strings.Builder)
+	if cfg.images {
+		// the before image is read, and its rows locked, by the same statement that updates them,
+		// such that it holds the values that are overwritten
+		before, _ := selectStatement(set, filter)
+		qb.WriteString("WITH before AS (")
+		qb.WriteString(before)
+		qb.WriteString(" FOR UPDATE), after AS (")
+	}
+	qb.WriteString("UPDATE ")
+	qb.WriteString(set.SchemaName)
+	qb.WriteRune('.')
+	qb.WriteString(set.TableName)
+	qb.WriteString(" SET ")
+	for _, each := range set.ColumnSchemas {
+		value, ok := record[each.Name]
+		if !ok {
+			continue
+		}
+		if each.IsPrimarykey {
+			return result, fmt.Errorf("primary key column %q of table %s.%s cannot be updated", each.Name, set.SchemaName, set.TableName)
+		}
+		if len(args) > keyCount {
+			qb.WriteRune(',')
+		}
+		args = append(args, value)
+		fmt.Fprintf(qb, "%s=$%d", quoteIdentifier(each.Name), len(args))
+	}
+	if len(args) == keyCount {
+		return result, fmt.Errorf("no columns to update in table %s.%s", set.SchemaName, set.TableName)
+	}
+	qb.WriteString(" WHERE ")
+	filter.whereOn(qb)
+	if cfg.images {
+		returningOn(qb, set)
+		qb.WriteString(") SELECT false,* FROM before UNION ALL SELECT true,* FROM after")
+	}
+	sql := qb.String()
+	slog.Debug("UpdateRecord", "sql", sql, "params", args)
+	dbrows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return result, err
+	}
+	if !cfg.images {
+		dbrows.Close()
+		result.RowsAffected = dbrows.CommandTag().RowsAffected()
+		return result, dbrows.Err()
+	}
+	// each image row is marked by whether it is after the update; a column name cannot be empty
+	imageSet := &pb.RowSet{ColumnSchemas: append([]*pb.ColumnSchema{{Name: "", TypeOid: pgtype.BoolOID}}, set.ColumnSchemas...)}
+	images := &objectCollector{set: imageSet}
+	if err := collectValues(dbrows, imageSet, images); err != nil {
+		return result, err
+	}
+	for _, each := range images.list {
+		after, _ := each[""].(bool)
+		delete(each, "")
+		if after {
+			result.After = append(result.After, each)
+		} else {
+			result.Before = append(result.Before, each)
+		}
+	}
+	result.RowsAffected = int64(len(result.After))
+	return result, nil
+}
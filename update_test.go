@@ -0,0 +1,72 @@
+package anyrow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateRecord(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7), "shoe"}}
+	result, err := UpdateRecord(ctx, conn, "testpkkey", "pktest", NewPrimaryKeyAndValues("id", 7), Record{"str": "shoe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `UPDATE public.pktest SET "str"=$2 WHERE id IN ($1)`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := conn.args, []any{7, "shoe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := result.RowsAffected, int64(1); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestUpdateRecordImages(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{results: [][][]any{{{false, int64(7), "boot"}, {true, int64(7), "shoe"}}}}
+	result, err := UpdateRecord(ctx, conn, "testpkkey", "pktest", NewPrimaryKeyAndValues("id", 7), Record{"str": "shoe"}, WriteImages())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// one statement locks and reads the rows before updating them
+	if got, want := conn.sql, `WITH before AS (SELECT "id","str" FROM public.pktest WHERE id IN ($1) FOR UPDATE), `+
+		`after AS (UPDATE public.pktest SET "str"=$2 WHERE id IN ($1) RETURNING "id","str") `+
+		`SELECT false,* FROM before UNION ALL SELECT true,* FROM after`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := result.Before, []Record{{"id": int64(7), "str": "boot"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := result.After, []Record{{"id": int64(7), "str": "shoe"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+	if got, want := result.RowsAffected, int64(1); got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestUpdateRecordPrimaryKey(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	_, err := UpdateRecord(ctx, conn, "testpkkey", "pktest", NewPrimaryKeyAndValues("id", 7), Record{"id": 8})
+	if err == nil {
+		t.Fatal("error expected")
+	}
+	if conn.sql != "" {
+		t.Errorf("unexpected query: %q", conn.sql)
+	}
+}
+
+func TestUpdateRecordNonKeyColumn(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	if _, err := UpdateRecord(ctx, conn, "testpkkey", "pktest", NewPrimaryKeyAndValues("str", "shoe"), Record{"str": "boot"}); err == nil {
+		t.Fatal("error expected")
+	}
+	if conn.sql != "" {
+		t.Errorf("unexpected query: %q", conn.sql)
+	}
+}
@@ -0,0 +1,134 @@
+package anyrow
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+// UpsertConflictColumns sets the columns of the unique constraint that detects a conflict.
+// Without this option, the primary key columns are used.
+func UpsertConflictColumns(names ...string) writeOption {
+	return func(w writeConfig) writeConfig {
+		w.conflictColumns = names
+		return w
+	}
+}
+
+// UpsertConflictConstraint sets the name of the unique constraint that detects a conflict.
+func UpsertConflictConstraint(name string) writeOption {
+	return func(w writeConfig) writeConfig {
+		w.conflictConstraint = name
+		return w
+	}
+}
+
+// UpsertSkipColumns excludes columns from being updated when a row conflicts.
+func UpsertSkipColumns(names ...string) writeOption {
+	return func(w writeConfig) writeConfig {
+		w.skipColumns = append(w.skipColumns, names...)
+		return w
+	}
+}
+
+// UpsertDoNothing leaves a conflicting row unchanged instead of updating it.
+func UpsertDoNothing() writeOption {
+	return func(w writeConfig) writeConfig {
+		w.doNothing = true
+		return w
+	}
+}
+
+// UpsertRecords inserts each Record as a new row or, if it conflicts with an existing row, updates the non-key columns of that row.
+// It returns the primary key values of the inserted or updated rows; rows left unchanged by UpsertDoNothing are not returned.
+// Keys of a Record that are not columns of the table are ignored, unless the WriteStrict option is given.
+func UpsertRecords(ctx context.Context, conn Querier, metadataCacheKey, tableName string, records []Record, options ...writeOption) ([]Record, error) {
+	set, err := cachedMetadata(ctx, conn, metadataCacheKey, tableName)
+	if err != nil {
+		return nil, err
+	}
+	return upsertRecords(ctx, conn, set, records, options)
+}
+
+// UpsertRowSet upserts all rows of a RowSet into its table, see UpsertRecords and InsertRowSet.
+func UpsertRowSet(ctx context.Context, conn Querier, metadataCacheKey string, set *pb.RowSet, options ...writeOption) ([]Record, error) {
+	metaSet, err := cachedMetadata(ctx, conn, metadataCacheKey, rowSetTableName(set))
+	if err != nil {
+		return nil, err
+	}
+	return upsertRecords(ctx, conn, metaSet, rowSetRecords(metaSet, set), options)
+}
+
+func upsertRecords(ctx context.Context, conn Querier, metaSet *pb.RowSet, records []Record, options []writeOption) ([]Record, error) {
+	cfg := newWriteConfig(options)
+	cfg.upsert = true
+	if err := cfg.checkConflictColumns(metaSet); err != nil {
+		return nil, err
+	}
+	return insertRecords(ctx, conn, metaSet, records, cfg)
+}
+
+// checkConflictColumns returns an error if a named column does not exist or if there is nothing to detect a conflict with.
+func (w writeConfig) checkConflictColumns(metaSet *pb.RowSet) error {
+	for _, each := range append(slices.Clone(w.conflictColumns), w.skipColumns...) {
+		if columnSchemaNamed(metaSet, each) == nil {
+			return fmt.Errorf("column %q does not exist in table %s.%s", each, metaSet.SchemaName, metaSet.TableName)
+		}
+	}
+	if w.conflictConstraint == "" && len(w.conflictTarget(metaSet)) == 0 {
+		return fmt.Errorf("table %s.%s has no primary key, conflict columns are required", metaSet.SchemaName, metaSet.TableName)
+	}
+	return nil
+}
+
+// conflictTarget returns the conflict columns or the primary key columns if none are set.
+func (w writeConfig) conflictTarget(metaSet *pb.RowSet) (columns []string) {
+	if len(w.conflictColumns) > 0 {
+		return w.conflictColumns
+	}
+	for _, each := range primaryKeySet(metaSet).ColumnSchemas {
+		columns = append(columns, each.Name)
+	}
+	return
+}
+
+// onConflictOn writes the ON CONFLICT clause for the columns of the record.
+func (w writeConfig) onConflictOn(b *strings.Builder, metaSet *pb.RowSet, record Record) {
+	b.WriteString(" ON CONFLICT ")
+	target := w.conflictTarget(metaSet)
+	if w.conflictConstraint != "" {
+		b.WriteString("ON CONSTRAINT ")
+		b.WriteString(quoteIdentifier(w.conflictConstraint))
+	} else {
+		b.WriteRune('(')
+		for i, each := range target {
+			if i > 0 {
+				b.WriteRune(',')
+			}
+			b.WriteString(quoteIdentifier(each))
+		}
+		b.WriteRune(')')
+	}
+	updates := []string{}
+	if !w.doNothing {
+		for _, each := range metaSet.ColumnSchemas {
+			if _, ok := record[each.Name]; !ok {
+				continue
+			}
+			if each.IsPrimarykey || slices.Contains(target, each.Name) || slices.Contains(w.skipColumns, each.Name) {
+				continue
+			}
+			name := quoteIdentifier(each.Name)
+			updates = append(updates, name+"=EXCLUDED."+name)
+		}
+	}
+	if len(updates) == 0 {
+		b.WriteString(" DO NOTHING")
+		return
+	}
+	b.WriteString(" DO UPDATE SET ")
+	b.WriteString(strings.Join(updates, ","))
+}
@@ -0,0 +1,51 @@
+package anyrow
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUpsertRecords(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7)}}
+	_, err := UpsertRecords(ctx, conn, "testpkkey", "pktest", []Record{{"id": 7, "str": "shoe"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `INSERT INTO public.pktest ("id","str") VALUES ($1,$2) ON CONFLICT ("id") DO UPDATE SET "str"=EXCLUDED."str" RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestUpsertRecordsDoNothing(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7)}}
+	_, err := UpsertRecords(ctx, conn, "testpkkey", "pktest", []Record{{"id": 7, "str": "shoe"}}, UpsertDoNothing())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `INSERT INTO public.pktest ("id","str") VALUES ($1,$2) ON CONFLICT ("id") DO NOTHING RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestUpsertRecordsConflictColumnsAndSkip(t *testing.T) {
+	ctx := context.Background()
+	conn := &mockQuerier{values: []any{int64(7)}}
+	_, err := UpsertRecords(ctx, conn, "testpkkey", "pktest", []Record{{"str": "shoe"}}, UpsertConflictColumns("str"), UpsertSkipColumns("id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conn.sql, `INSERT INTO public.pktest ("str") VALUES ($1) ON CONFLICT ("str") DO NOTHING RETURNING "id"`; got != want {
+		t.Errorf("got [%v:%T] want [%v:%T]", got, got, want, want)
+	}
+}
+
+func TestUpsertRecordsUnknownConflictColumn(t *testing.T) {
+	ctx := context.Background()
+	conn := new(mockQuerier)
+	_, err := UpsertRecords(ctx, conn, "testpkkey", "pktest", []Record{{"str": "shoe"}}, UpsertConflictColumns("missing"))
+	if err == nil {
+		t.Fatal("error expected")
+	}
+}
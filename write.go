@@ -0,0 +1,172 @@
+package anyrow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/emicklei/anyrow/pb"
+)
+
+type writeOption func(w writeConfig) writeConfig
+
+type writeConfig struct {
+	strict        bool
+	images        bool
+	nonKeyColumns bool
+	// upsert only
+	upsert             bool
+	conflictColumns    []string
+	conflictConstraint string
+	skipColumns        []string
+	doNothing          bool
+	// insert of a RowSet and load only
+	overriding bool
+}
+
+// WriteResult holds the outcome of an update or delete.
+type WriteResult struct {
+	RowsAffected int64
+	// Before holds the rows before the write, only if the WriteImages option is given.
+	Before []Record
+	// After holds the rows after the write, only if the WriteImages option is given.
+	After []Record
+}
+
+// WriteStrict makes a write fail if a Record has a key that is not a column of the table.
+// Without this option, such keys are ignored.
+func WriteStrict() writeOption {
+	return func(w writeConfig) writeConfig {
+		w.strict = true
+		return w
+	}
+}
+
+// WriteNonKeyColumns allows UpdateRecord and DeleteRecords to select rows by columns that are not part of the primary key,
+// or by only some columns of a composite primary key.
+// Without this option, such a write is refused because it can affect any number of rows.
+func WriteNonKeyColumns() writeOption {
+	return func(w writeConfig) writeConfig {
+		w.nonKeyColumns = true
+		return w
+	}
+}
+
+// WriteImages makes an update or delete return the rows before and after the write.
+func WriteImages() writeOption {
+	return func(w writeConfig) writeConfig {
+		w.images = true
+		return w
+	}
+}
+
+func newWriteConfig(options []writeOption) writeConfig {
+	cfg := writeConfig{}
+	for _, each := range options {
+		cfg = each(cfg)
+	}
+	return cfg
+}
+
+// checkColumns returns an error if strict and the record has a key that is not a column of the table.
+func (w writeConfig) checkColumns(metaSet *pb.RowSet, record Record) error {
+	if !w.strict {
+		return nil
+	}
+	for key := range record {
+		if columnSchemaNamed(metaSet, key) == nil {
+			return fmt.Errorf("column %q does not exist in table %s.%s", key, metaSet.SchemaName, metaSet.TableName)
+		}
+	}
+	return nil
+}
+
+// checkEnumValues returns an error if the record has a string value for an enum column that is not one of its labels.
+func checkEnumValues(metaSet *pb.RowSet, record Record) error {
+	for key, value := range record {
+		label, ok := value.(string)
+		if !ok {
+			continue
+		}
+		schema := columnSchemaNamed(metaSet, key)
+		if schema == nil || len(schema.EnumLabels) == 0 {
+			continue
+		}
+		if !slices.Contains(schema.EnumLabels, label) {
+			return fmt.Errorf("value %q of column %q is not one of %v", label, key, schema.EnumLabels)
+		}
+	}
+	return nil
+}
+
+// checkKeyColumns returns an error if the key has no values or refers to a column that is not part of the table.
+// Unless the WriteNonKeyColumns option is given, the key columns must be exactly those of the primary key,
+// such that the key identifies a single row.
+func (w writeConfig) checkKeyColumns(metaSet *pb.RowSet, pkv PrimaryKeysAndValues) error {
+	if !pkv.hasValues() {
+		return fmt.Errorf("missing key values for table %s.%s", metaSet.SchemaName, metaSet.TableName)
+	}
+	columns := []string{pkv.column}
+	if pkv.column == "" {
+		columns = columns[:0]
+		for _, each := range pkv.pairs {
+			columns = append(columns, each.Column)
+		}
+	}
+	for _, each := range columns {
+		schema := columnSchemaNamed(metaSet, each)
+		if schema == nil {
+			return fmt.Errorf("key column %q does not exist in table %s.%s", each, metaSet.SchemaName, metaSet.TableName)
+		}
+		if !schema.IsPrimarykey && !w.nonKeyColumns {
+			return fmt.Errorf("key column %q is not part of the primary key of table %s.%s", each, metaSet.SchemaName, metaSet.TableName)
+		}
+	}
+	if w.nonKeyColumns {
+		return nil
+	}
+	for _, each := range metaSet.ColumnSchemas {
+		if each.IsPrimarykey && !slices.Contains(columns, each.Name) {
+			return fmt.Errorf("missing key values for primary key column %q of table %s.%s", each.Name, metaSet.SchemaName, metaSet.TableName)
+		}
+	}
+	return nil
+}
+
+// returningOn writes a RETURNING clause for all columns of the set, if any.
+// Columns are projected as when fetched, such that returned rows equal fetched rows.
+func returningOn(b *strings.Builder, set *pb.RowSet) {
+	filter := fetchFilter{}
+	for i, each := range set.ColumnSchemas {
+		if i == 0 {
+			b.WriteString(" RETURNING ")
+		} else {
+			b.WriteRune(',')
+		}
+		filter.columnOn(b, each)
+	}
+}
+
+// primaryKeySet returns a RowSet with only the primary key column schemas of the metadata set.
+func primaryKeySet(metaSet *pb.RowSet) *pb.RowSet {
+	set := &pb.RowSet{
+		SchemaName: metaSet.SchemaName,
+		TableName:  metaSet.TableName,
+	}
+	for _, each := range metaSet.ColumnSchemas {
+		if each.IsPrimarykey {
+			set.ColumnSchemas = append(set.ColumnSchemas, each)
+		}
+	}
+	return set
+}
+
+// columnSchemaNamed returns the column schema with the given name or nil if absent.
+func columnSchemaNamed(metaSet *pb.RowSet, name string) *pb.ColumnSchema {
+	for _, each := range metaSet.ColumnSchemas {
+		if each.Name == name {
+			return each
+		}
+	}
+	return nil
+}